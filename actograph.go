@@ -2,21 +2,47 @@ package actograph
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
 
 	"github.com/actord/actograph/directive"
 )
 
-var hardcodedDirectives = []string{"enumPrivacy", "enumVal"}
+var hardcodedDirectives = []string{
+	enumPrivacyDirectiveName,
+	"enumVal",
+	noInheritDirectiveName,
+	visibilityDirectiveName,
+	internalDirectiveName,
+	connectionDirectiveName,
+	nodeDirectiveName,
+	specifiedByDirectiveName,
+}
+
+// isHardcodedDirective reports is directive handled by actograph itself, such directives are not registered
+func isHardcodedDirective(name string) bool {
+	for _, hardcodedDirective := range hardcodedDirectives {
+		if hardcodedDirective == name {
+			return true
+		}
+	}
+	return false
+}
 
 type Actograph struct {
-	directiveDeclarations map[string]directive.Definition
+	// directives, resolvers, scalars and settings registered in Go code
+	registrations
 
 	// fill definitions while parse "schema.graphql" file
 	schema                 *ast.SchemaDefinition
@@ -25,21 +51,56 @@ type Actograph struct {
 	inputObjectDefinitions map[string]*ast.InputObjectDefinition
 	enumDefinitions        map[string]*ast.EnumDefinition
 	unionDefinitions       map[string]*ast.UnionDefinition
+	interfaceDefinitions   map[string]*ast.InterfaceDefinition
 	declaredScalars        map[string]ScalarDefinition // map name to description
 	extensionDefinitions   map[string][]*ast.TypeExtensionDefinition
+	// extensions of other kinds not merged yet, see mergeTypeExtensions
+	typeExtensions []typeExtension
+	// fields marked by @connection, their types are replaced by connection types while making schema
+	connectionFields map[*ast.FieldDefinition]bool
+	// resolvers of generated node and nodes query fields and id fields of types marked by @node
+	nodeResolvers map[*ast.FieldDefinition]directive.Directive
+	// resolvers of fields made from registered functions and bound methods while making schema
+	fieldResolvers map[*ast.FieldDefinition]directive.Directive
 
 	// resulting objects, fill while making schema
 	enums        map[string]*graphql.Enum
 	objects      map[string]*graphql.Object
 	inputObjects map[string]*graphql.InputObject
 	scalars      map[string]*graphql.Scalar
-	unions       map[string]*graphql.Union
+	// invalid default values of arguments and input fields found while making schema
+	defaultValueErrors []error
+	unions             map[string]*graphql.Union
+	interfaces         map[string]*graphql.Interface
 
-	lazySchema           *graphql.Schema
 	lazySchemaDirectives []directive.Directive
+	// executable is set by Build, after that actograph can't be changed
+	executable atomic.Pointer[Executable]
+	// buildMu serializes building and validation
+	buildMu sync.Mutex
+
+	// audience of schema variant currently being made and types hidden for it (see @visibility and @internal)
+	audience     string
+	hideInternal bool
+	hiddenTypes  map[string]bool
+
+	// true when some elements are marked by @internal, so introspection is executed on separate schema variant
+	hasInternal bool
+	// schema directives implementing directive.FieldsScoped, executed for every field
+	schemaFieldDirectives []inheritedDirective
+	// true when schema variant being made declares directives that clients can write in operations
+	hasExecutableDirectives bool
+
+	// readSources reads schema files actograph was made from, it is nil when actograph can't be reloaded
+	readSources func() ([]schemaFile, error)
+	// fingerprint of schema files actograph was made from
+	sourcesFingerprint [sha256.Size]byte
 }
 
 func (agh *Actograph) RegisterDirective(dir directive.Definition) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
 	if _, has := agh.directiveDeclarations[dir.Name()]; has {
 		return fmt.Errorf("directive @%s already registered", dir.Name())
 	}
@@ -66,8 +127,49 @@ func (agh *Actograph) RegisterDirectives(dirs ...directive.Definition) error {
 	return nil
 }
 
+// RegisterScalar registers scalar which functions are passed to graphql as is, see RegisterScalarE
 func (agh *Actograph) RegisterScalar(cfg ScalarConfig) error {
-	newS := graphql.NewScalar(graphql.ScalarConfig{
+	if err := agh.checkScalarRegistrable(cfg.Name); err != nil {
+		return err
+	}
+
+	agh.scalars[cfg.Name] = newLegacyScalar(cfg)
+	agh.legacyScalarConfigs[cfg.Name] = cfg
+
+	return nil
+}
+
+// RegisterScalarE registers scalar which functions report errors
+func (agh *Actograph) RegisterScalarE(cfg ScalarConfigE) error {
+	if err := agh.checkScalarRegistrable(cfg.Name); err != nil {
+		return err
+	}
+
+	agh.scalars[cfg.Name] = newScalar(cfg)
+	agh.scalarConfigs[cfg.Name] = cfg
+
+	return nil
+}
+
+func (agh *Actograph) checkScalarRegistrable(name string) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	if _, has := agh.scalarConfigs[name]; has {
+		return fmt.Errorf("scalar '%s' already registered", name)
+	}
+	if _, has := agh.legacyScalarConfigs[name]; has {
+		return fmt.Errorf("scalar '%s' already registered", name)
+	}
+	if _, has := agh.scalars[name]; has && !agh.allowBuiltinScalarOverride {
+		return fmt.Errorf("scalar '%s' is builtin, use AllowBuiltinScalarOverride to replace it", name)
+	}
+	return nil
+}
+
+// newLegacyScalar makes graphql scalar of scalar config registered by RegisterScalar
+func newLegacyScalar(cfg ScalarConfig) *graphql.Scalar {
+	return graphql.NewScalar(graphql.ScalarConfig{
 		Name:        cfg.Name,
 		Description: cfg.Description,
 		Serialize: func(value interface{}) interface{} {
@@ -80,9 +182,32 @@ func (agh *Actograph) RegisterScalar(cfg ScalarConfig) error {
 			return cfg.ParseLiteral(valueAST)
 		},
 	})
-	agh.scalars[cfg.Name] = newS
+}
 
-	return nil
+// newScalar makes graphql scalar of registered scalar config
+func newScalar(cfg ScalarConfigE) *graphql.Scalar {
+	return graphql.NewScalar(graphql.ScalarConfig{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		Serialize: func(value interface{}) interface{} {
+			serialized, err := serializeScalar(cfg, value)
+			if err != nil {
+				// panic is reported by graphql as error of field
+				panic(err)
+			}
+			return serialized
+		},
+		// nil is reported by graphql as invalid value, errors of scalar are reported before by validation
+		// (see scalarLiteralErrors and variableErrors)
+		ParseValue: func(value interface{}) interface{} {
+			parsed, _ := parseScalarValue(cfg, value)
+			return parsed
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			parsed, _ := parseScalarLiteral(cfg, valueAST)
+			return parsed
+		},
+	})
 }
 
 func (agh *Actograph) RegisterScalars(cfgs ...ScalarConfig) error {
@@ -96,6 +221,46 @@ func (agh *Actograph) RegisterScalars(cfgs ...ScalarConfig) error {
 	return nil
 }
 
+// AllowBuiltinScalarOverride allows to replace builtin scalars (String, Int, Float, Boolean, ID and DateTime)
+// by RegisterScalar and RegisterScalarE
+func (agh *Actograph) AllowBuiltinScalarOverride() error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	agh.allowBuiltinScalarOverride = true
+	return nil
+}
+
+// Warnings returns problems of schema that don't prevent it from working, like scalars registered
+// but not declared in schema
+func (agh *Actograph) Warnings() []string {
+	var warnings []string
+	checkDeclared := func(name string) {
+		if _, declared := agh.declaredScalars[name]; !declared {
+			warnings = append(warnings, fmt.Sprintf("scalar '%s' is registered, but not declared in schema", name))
+		}
+	}
+	for name := range agh.scalarConfigs {
+		checkDeclared(name)
+	}
+	for name := range agh.legacyScalarConfigs {
+		checkDeclared(name)
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+func (agh *Actograph) RegisterScalarsE(cfgs ...ScalarConfigE) error {
+	var err error
+	for i, cfg := range cfgs {
+		err = agh.RegisterScalarE(cfg)
+		if err != nil {
+			return fmt.Errorf("while registering scalar at index '%d': %w", i, err)
+		}
+	}
+	return nil
+}
+
 func (agh *Actograph) ConstructDirective(dir *ast.Directive, node ast.Node) (directive.Directive, error) {
 	name := dir.Name.Value
 	declaration, has := agh.directiveDeclarations[name]
@@ -132,9 +297,19 @@ func (agh *Actograph) makeDirectiveArguments(dir *ast.Directive, dirDefinition *
 }
 
 func (agh *Actograph) Parse(graphqlFile []byte) error {
+	return agh.parseSource("", graphqlFile)
+}
+
+// parseSource parses schema file, its name is used in locations of errors
+func (agh *Actograph) parseSource(name string, graphqlFile []byte) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	graphqlFile, extensionStarts := markExtensions(graphqlFile)
 	astDoc, err := parser.Parse(parser.ParseParams{
 		Source: &source.Source{
 			Body: graphqlFile,
+			Name: name,
 		},
 	})
 	if err != nil {
@@ -142,95 +317,168 @@ func (agh *Actograph) Parse(graphqlFile []byte) error {
 	}
 
 	for _, node := range astDoc.Definitions {
-		switch node.GetKind() {
-		case "DirectiveDefinition":
-			n := node.(*ast.DirectiveDefinition)
-			agh.addDirective(n)
-		case "ObjectDefinition":
-			n := node.(*ast.ObjectDefinition)
-			agh.addObject(n)
-		case "InputObjectDefinition":
-			n := node.(*ast.InputObjectDefinition)
-			agh.addInputObject(n)
-		case "SchemaDefinition":
-			n := node.(*ast.SchemaDefinition)
-			agh.addSchema(n)
-		case "EnumDefinition":
-			n := node.(*ast.EnumDefinition)
-			agh.addEnum(n)
-		case "ScalarDefinition":
-			n := node.(*ast.ScalarDefinition)
-			agh.addScalar(n)
-		case "UnionDefinition":
-			n := node.(*ast.UnionDefinition)
-			agh.addUnion(n)
-		case "TypeExtensionDefinition":
-			n := node.(*ast.TypeExtensionDefinition)
-			agh.addExtensionDefinition(n)
-		default:
-			panic(fmt.Errorf("unknown node kind: %s", node.GetKind()))
+		if extension, isExtension := extensionStarts[node.GetLoc().Start]; isExtension {
+			agh.addTypeExtension(extension, node)
+			continue
 		}
+		agh.addDefinition(node)
 	}
 
 	return nil
 }
 
+// addDefinition adds parsed or built definition, it panics when definition is already defined
+func (agh *Actograph) addDefinition(node ast.Node) {
+	switch node.GetKind() {
+	case "DirectiveDefinition":
+		n := node.(*ast.DirectiveDefinition)
+		agh.addDirective(n)
+	case "ObjectDefinition":
+		n := node.(*ast.ObjectDefinition)
+		agh.addObject(n)
+	case "InputObjectDefinition":
+		n := node.(*ast.InputObjectDefinition)
+		agh.addInputObject(n)
+	case "SchemaDefinition":
+		n := node.(*ast.SchemaDefinition)
+		agh.addSchema(n)
+	case "EnumDefinition":
+		n := node.(*ast.EnumDefinition)
+		agh.addEnum(n)
+	case "ScalarDefinition":
+		n := node.(*ast.ScalarDefinition)
+		agh.addScalar(n)
+	case "UnionDefinition":
+		n := node.(*ast.UnionDefinition)
+		agh.addUnion(n)
+	case "InterfaceDefinition":
+		n := node.(*ast.InterfaceDefinition)
+		agh.addInterface(n)
+	case "TypeExtensionDefinition":
+		n := node.(*ast.TypeExtensionDefinition)
+		agh.addExtensionDefinition(n)
+	default:
+		panic(fmt.Errorf("unknown node kind: %s", node.GetKind()))
+	}
+}
+
 func (agh *Actograph) Validate() error {
-	// when schema created - validation already passed
-	if agh.lazySchema != nil {
+	// when schema built - validation already passed
+	if agh.executable.Load() != nil {
 		return nil
 	}
+	agh.buildMu.Lock()
+	defer agh.buildMu.Unlock()
 	_, err := agh.makeSchema()
 	return err
 }
 
+// Schema builds actograph (see Build) and returns full schema
 func (agh *Actograph) Schema() (graphql.Schema, error) {
-	if agh.lazySchema != nil {
-		return *agh.lazySchema, nil
+	exe, err := agh.Build()
+	if err != nil {
+		return graphql.Schema{}, err
 	}
-	schema, err := agh.makeSchema()
+	return exe.Schema(), nil
+}
+
+// SchemaFor builds actograph (see Build) and returns variant of schema for audience: types, fields, arguments
+// and enum values hidden by @visibility(audiences: [String!]!) are removed, as well as types that became empty
+// or unreachable. Empty audience means full schema, the same as Schema returns
+func (agh *Actograph) SchemaFor(audience string) (graphql.Schema, error) {
+	exe, err := agh.Build()
 	if err != nil {
 		return graphql.Schema{}, err
 	}
-	agh.lazySchema = &schema
-	return schema, nil
+	return exe.SchemaFor(audience), nil
+}
+
+// schemaVariant identifies variant of schema
+type schemaVariant struct {
+	audience string
+	// introspection variant is served for introspection queries: elements marked by @internal are removed
+	introspection bool
+}
+
+// makeVariant makes variant of schema, see Build
+func (agh *Actograph) makeVariant(variant schemaVariant) (graphql.Schema, error) {
+	agh.audience, agh.hideInternal = variant.audience, variant.introspection
+	schema, err := agh.makeSchema()
+	agh.audience, agh.hideInternal = "", false
+	if err != nil && variant.audience != "" {
+		return graphql.Schema{}, fmt.Errorf("when making schema for audience '%s': %w", variant.audience, err)
+	}
+	return schema, err
 }
 
 func (agh *Actograph) makeSchema() (graphql.Schema, error) {
+	if err := agh.mergeTypeExtensions(); err != nil {
+		return graphql.Schema{}, err
+	}
+	if err := agh.validateEnumPrivacy(); err != nil {
+		return graphql.Schema{}, err
+	}
+
+	if err := agh.makeConnections(); err != nil {
+		return graphql.Schema{}, err
+	}
+	if err := agh.makeNodes(); err != nil {
+		return graphql.Schema{}, err
+	}
+	if err := agh.makeFieldResolvers(); err != nil {
+		return graphql.Schema{}, err
+	}
+	agh.hasInternal = agh.hasInternalElements()
+
+	// every schema variant has own types
+	agh.enums = map[string]*graphql.Enum{}
+	agh.objects = map[string]*graphql.Object{}
+	agh.inputObjects = map[string]*graphql.InputObject{}
+	agh.unions = map[string]*graphql.Union{}
+	agh.interfaces = map[string]*graphql.Interface{}
+	agh.computeHiddenTypes()
+
 	// check is all declared directions are defined
 	for directiveDefinitionName := range agh.directiveDefinitions {
 		if _, has := agh.directiveDeclarations[directiveDefinitionName]; !has {
-			hasHardcoded := false
-			for _, hardcodedDirective := range hardcodedDirectives {
-				if hardcodedDirective == directiveDefinitionName {
-					hasHardcoded = true
-					break
-				}
-			}
-			if hasHardcoded {
+			if isHardcodedDirective(directiveDefinitionName) {
 				continue
 			}
 			return graphql.Schema{}, fmt.Errorf("directive '%s' was declared in schema, but not registered", directiveDefinitionName)
 		}
 	}
 
-	// make lazySchemaDirectives
-	agh.lazySchemaDirectives = make([]directive.Directive, len(agh.schema.Directives))
-	for i, dir := range agh.schema.Directives {
-		var err error
-		agh.lazySchemaDirectives[i], err = agh.ConstructDirective(dir, agh.schema)
+	// make lazySchemaDirectives and schemaFieldDirectives
+	agh.lazySchemaDirectives = make([]directive.Directive, 0, len(agh.schema.Directives))
+	agh.schemaFieldDirectives = nil
+	for _, dir := range agh.schema.Directives {
+		schemaDirective, err := agh.ConstructDirective(dir, agh.schema)
 		if err != nil {
 			panic(fmt.Errorf("error when contructing directive: %w", err))
 		}
+		if scoped, ok := schemaDirective.(directive.FieldsScoped); ok && scoped.AppliesToFields() {
+			agh.schemaFieldDirectives = append(agh.schemaFieldDirectives, inheritedDirective{
+				name:      dir.Name.Value,
+				directive: schemaDirective,
+			})
+			continue
+		}
+		agh.lazySchemaDirectives = append(agh.lazySchemaDirectives, schemaDirective)
 	}
+	agh.lazySchemaDirectives = directive.SortByPhase(agh.lazySchemaDirectives)
 
 	for _, scalarDefinition := range agh.declaredScalars {
 		if _, has := agh.scalars[scalarDefinition.Name]; !has {
-			panic(fmt.Errorf("scalar '%s' was declared by not defined", scalarDefinition.Name))
+			return graphql.Schema{}, fmt.Errorf("scalar '%s' was declared in schema at %s, but not registered",
+				scalarDefinition.Name, locationString(scalarDefinition.Loc))
 		}
 	}
 
+	// resolvers of fields made below capture it, so every variant executes own client directives
+	agh.hasExecutableDirectives = len(agh.executableDirectiveNames()) > 0
+
 	gconf := graphql.SchemaConfig{}
+	agh.defaultValueErrors = nil
 	agh.makeEmptyObjects()
 	agh.fillCachedObjectsWithFields()
 
@@ -246,6 +494,13 @@ func (agh *Actograph) makeSchema() (graphql.Schema, error) {
 		}
 	}
 
+	if agh.hiddenTypes[queryTypename] {
+		return graphql.Schema{}, fmt.Errorf("schema.query type '%s' is hidden for audience '%s'", queryTypename, agh.audience)
+	}
+	if agh.hiddenTypes[mutationTypename] {
+		mutationTypename = ""
+	}
+
 	if queryTypename != "" {
 		queryType, has := agh.objects[queryTypename]
 		if !has {
@@ -264,56 +519,46 @@ func (agh *Actograph) makeSchema() (graphql.Schema, error) {
 
 	// TODO: implement subscription pls ^_^
 
+	var argTypes []graphql.Type
+	gconf.Directives, argTypes = agh.makeExecutableDirectives()
+	gconf.Types = append(gconf.Types, argTypes...)
+	if len(agh.defaultValueErrors) > 0 {
+		return graphql.Schema{}, errors.Join(agh.defaultValueErrors...)
+	}
+
+	// implementations of interfaces may be reachable only through interface
+	for name, objDefinition := range agh.objectDefinitions {
+		if agh.hiddenTypes[name] || agh.objects[name] == nil {
+			continue
+		}
+		if len(agh.objectInterfaces(name, objDefinition)) > 0 {
+			gconf.Types = append(gconf.Types, agh.objects[name])
+		}
+	}
+
 	return graphql.NewSchema(gconf)
 }
 
+// Do builds actograph (see Build) and executes request
 func (agh *Actograph) Do(request RequestQuery) (*Result, error) {
-	schema, err := agh.Schema()
+	exe, err := agh.Build()
 	if err != nil {
 		return nil, fmt.Errorf("when taking schema: %w", err)
 	}
-
-	ctx := request.Context
-	if ctx == nil {
-		ctx = context.Background()
-	}
-
-	var rootObject map[string]interface{}
-	if request.RootObject == nil {
-		rootObject = map[string]interface{}{}
-	} else {
-		rootObject = request.RootObject
-	}
-
-	var resolvedValue interface{}
-	resolvedValue, ctx, err = agh.executeDirectives(ctx, rootObject, rootObject, map[string]interface{}{}, agh.lazySchemaDirectives)
-	// schema directives should return map[string]interface{}
-	if resolvedValueMap, ok := resolvedValue.(map[string]interface{}); ok {
-		rootObject = resolvedValueMap
-	}
-
-	result := graphql.Do(graphql.Params{
-		Schema:         schema,
-		RequestString:  request.RequestString,
-		VariableValues: request.VariableValues,
-		OperationName:  request.OperationName,
-		RootObject:     rootObject,
-		Context:        ctx,
-	})
-	if result == nil {
-		return nil, fmt.Errorf("unknown result")
-	}
-
-	return &Result{
-		Data:       result.Data,
-		Errors:     result.Errors,
-		Extensions: result.Extensions,
-	}, nil
+	return exe.Do(request)
 }
 
 func (agh *Actograph) fillCachedObjectsWithFields() {
 
 	for enumName, enumDefinition := range agh.enumDefinitions {
+		if agh.getEnumPrivacy(enumDefinition).backendOnly() {
+			// used only in directive arguments, those are taken as literals
+			continue
+		}
+		if agh.hiddenTypes[enumName] {
+			continue
+		}
+
 		var description string
 		if enumDefinition.Description != nil {
 			description = enumDefinition.Description.Value
@@ -321,6 +566,9 @@ func (agh *Actograph) fillCachedObjectsWithFields() {
 
 		values := graphql.EnumValueConfigMap{}
 		for _, valueDefinition := range enumDefinition.Values {
+			if !agh.isVisible(valueDefinition.Directives) {
+				continue
+			}
 
 			name := valueDefinition.Name.Value
 			var valueDescription string
@@ -338,6 +586,9 @@ func (agh *Actograph) fillCachedObjectsWithFields() {
 					panic(err)
 				}
 			}
+			if binding, bound := agh.enumBindings[enumName]; bound {
+				valCfg.Value = binding[name]
+			}
 			values[name] = valCfg
 		}
 
@@ -349,36 +600,72 @@ func (agh *Actograph) fillCachedObjectsWithFields() {
 		agh.enums[enumName] = enum
 	}
 
+	// input objects are filled before objects, because default values of arguments are coerced to input objects
+	for inputObjName, inputObjDefinition := range agh.inputObjectDefinitions {
+		if agh.hiddenTypes[inputObjName] {
+			continue
+		}
+		for _, fieldDefinition := range inputObjDefinition.Fields {
+			if !agh.isArgumentVisible(fieldDefinition) {
+				continue
+			}
+			fieldName := fieldDefinition.Name.Value
+			fieldConfig := agh.makeInputField(fieldDefinition)
+			agh.inputObjects[inputObjName].AddFieldConfig(fieldName, fieldConfig)
+		}
+	}
+	agh.fillInputDefaultValues()
+
 	for objName, objDefinition := range agh.objectDefinitions {
+		if agh.hiddenTypes[objName] {
+			continue
+		}
+
+		// directives of schema and type are prepended to directives of every field
+		inherited := append(append([]inheritedDirective{}, agh.schemaFieldDirectives...), agh.makeTypeDirectives(objDefinition)...)
+
 		for _, fieldDefinition := range objDefinition.Fields {
+			if !agh.isFieldVisible(fieldDefinition) {
+				continue
+			}
 			fieldName := fieldDefinition.Name.Value
-			fieldConfig := agh.makeField(fieldDefinition)
+			fieldConfig := agh.makeField(fieldDefinition, inherited)
 			agh.objects[objName].AddFieldConfig(fieldName, fieldConfig)
 		}
 
 		if extended, has := agh.extensionDefinitions[objName]; has {
 			for _, ext := range extended {
 				for _, fieldDefinition := range ext.Definition.Fields {
+					if !agh.isFieldVisible(fieldDefinition) {
+						continue
+					}
 					fieldName := fieldDefinition.Name.Value
-					fieldConfig := agh.makeField(fieldDefinition)
+					fieldConfig := agh.makeField(fieldDefinition, inherited)
 					agh.objects[objName].AddFieldConfig(fieldName, fieldConfig)
 				}
 			}
 		}
 	}
 
-	for inputObjName, inputObjDefinition := range agh.inputObjectDefinitions {
-		for _, fieldDefinition := range inputObjDefinition.Fields {
-			fieldName := fieldDefinition.Name.Value
-			fieldConfig := agh.makeInputField(fieldDefinition)
-			agh.inputObjects[inputObjName].AddFieldConfig(fieldName, fieldConfig)
+	for interfaceName, interfaceDefinition := range agh.interfaceDefinitions {
+		if agh.hiddenTypes[interfaceName] {
+			continue
+		}
+		for _, fieldDefinition := range interfaceDefinition.Fields {
+			if !agh.isFieldVisible(fieldDefinition) {
+				continue
+			}
+			// interface fields are never resolved, only their types and arguments matter
+			agh.interfaces[interfaceName].AddFieldConfig(fieldDefinition.Name.Value, agh.makeField(fieldDefinition, nil))
 		}
 	}
 }
 
 func (agh *Actograph) makeInputField(fieldDefinition *ast.InputValueDefinition) *graphql.InputObjectFieldConfig {
-	if len(fieldDefinition.Directives) > 0 {
-		panic("input field directives are not supported yet")
+	for _, dir := range fieldDefinition.Directives {
+		if !isHardcodedDirective(dir.Name.Value) {
+			panic("input field directives are not supported yet")
+		}
 	}
 
 	var description string
@@ -386,25 +673,24 @@ func (agh *Actograph) makeInputField(fieldDefinition *ast.InputValueDefinition)
 		description = fieldDefinition.Description.Value
 	}
 
+	// default value is set by fillInputDefaultValues when all input objects have fields
 	return &graphql.InputObjectFieldConfig{
-		Type:         agh.getType(fieldDefinition.Type),
-		DefaultValue: fieldDefinition.DefaultValue,
-		Description:  description,
+		Type:        agh.getType(fieldDefinition.Type),
+		Description: description,
 	}
 }
 
-func (agh *Actograph) makeField(fieldDefinition *ast.FieldDefinition) *graphql.Field {
+func (agh *Actograph) makeField(fieldDefinition *ast.FieldDefinition, inherited []inheritedDirective) *graphql.Field {
 	var args graphql.FieldConfigArgument
 	if len(fieldDefinition.Arguments) > 0 {
 		args = graphql.FieldConfigArgument{}
 		for _, argDefinition := range fieldDefinition.Arguments {
+			if !agh.isArgumentVisible(argDefinition) {
+				continue
+			}
 			name := argDefinition.Name.Value
 			argType := agh.getType(argDefinition.Type)
 			// TODO: maybe we should check is argType is scalar or inputObject, because objects is not allowed as arguments
-			var defaultValue interface{}
-			if argDefinition.DefaultValue != nil {
-				defaultValue = argDefinition.DefaultValue.GetValue()
-			}
 			var description string
 			if argDefinition.Description != nil {
 				description = argDefinition.Description.Value
@@ -412,7 +698,7 @@ func (agh *Actograph) makeField(fieldDefinition *ast.FieldDefinition) *graphql.F
 
 			args[name] = &graphql.ArgumentConfig{
 				Type:         argType,
-				DefaultValue: defaultValue,
+				DefaultValue: agh.coerceDefaultValue(argType, argDefinition),
 				Description:  description,
 			}
 		}
@@ -423,13 +709,33 @@ func (agh *Actograph) makeField(fieldDefinition *ast.FieldDefinition) *graphql.F
 		description = fieldDefinition.Description.Value
 	}
 
-	directiveExecutables := agh.makeDirectives(fieldDefinition, fieldDefinition.Directives)
+	fieldDirectives := agh.makeDirectives(fieldDefinition, fieldDefinition.Directives)
+	directiveExecutables := append(agh.inheritDirectives(fieldDefinition, inherited), fieldDirectives...)
+	// trivial fields only take value from parent object: they have no directives (written on field or inherited
+	// from type or schema), no registered resolver or bound method and they are not resolved by @node or @connection
+	trivial := len(directiveExecutables) == 0
+	if agh.connectionFields[fieldDefinition] {
+		directiveExecutables = append(directiveExecutables, &connectionResolver{})
+		trivial = false
+	}
+	if nodeResolver, has := agh.nodeResolvers[fieldDefinition]; has {
+		directiveExecutables = append(directiveExecutables, nodeResolver)
+		trivial = false
+	}
+	if fieldResolver, has := agh.fieldResolvers[fieldDefinition]; has {
+		directiveExecutables = append(directiveExecutables, fieldResolver)
+		trivial = false
+	}
+	if enum := agh.enums[namedTypeName(fieldDefinition.Type)]; enum != nil {
+		directiveExecutables = append(directiveExecutables, &enumValueChecker{enum: enum})
+	}
+	directiveExecutables = directive.SortByPhase(directiveExecutables)
 
 	f := &graphql.Field{
 		Name:        fieldDefinition.Name.Value,
 		Type:        agh.getType(fieldDefinition.Type),
 		Args:        args,
-		Resolve:     agh.getFieldResolveFunc(directiveExecutables),
+		Resolve:     agh.getFieldResolveFunc(directiveExecutables, agh.hasExecutableDirectives),
 		Subscribe:   agh.getFieldSubscribeFunc(),
 		Description: description,
 	}
@@ -437,14 +743,36 @@ func (agh *Actograph) makeField(fieldDefinition *ast.FieldDefinition) *graphql.F
 	if err := agh.executeDefineDirectives(directiveExecutables, "*graphql.Field", f); err != nil {
 		panic(err)
 	}
+	resolve := FieldResolver(f.Resolve)
+	if len(agh.fieldMiddlewares) > 0 {
+		chained := chainFieldMiddlewares(agh.fieldMiddlewares, resolve)
+		switch {
+		case !trivial:
+			resolve = chained
+		case agh.hasExecutableDirectives:
+			// trivial fields skip field middlewares unless client wrote directives on them
+			skipped := resolve
+			resolve = func(p graphql.ResolveParams) (interface{}, error) {
+				if agh.hasFieldExecutableDirectives(p.Context, p.Info) {
+					return chained(p)
+				}
+				return skipped(p)
+			}
+		}
+	}
+	f.Resolve = omittableResolveFunc(resolve)
 
 	return f
 }
 
 func (agh *Actograph) makeDirectives(node ast.Node, directiveDefinitions []*ast.Directive) []directive.Directive {
-	directiveExecutables := make([]directive.Directive, len(directiveDefinitions))
-	for i, directiveUsageDefinition := range directiveDefinitions {
+	directiveExecutables := make([]directive.Directive, 0, len(directiveDefinitions))
+	for _, directiveUsageDefinition := range directiveDefinitions {
 		name := directiveUsageDefinition.Name.Value
+		if isHardcodedDirective(name) {
+			// handled by actograph itself (e.g. @noInherit by inheritDirectives)
+			continue
+		}
 		args := map[string]ast.Value{}
 		for _, arg := range directiveUsageDefinition.Arguments {
 			args[arg.Name.Value] = arg.Value
@@ -456,7 +784,7 @@ func (agh *Actograph) makeDirectives(node ast.Node, directiveDefinitions []*ast.
 		if err != nil {
 			panic(fmt.Errorf("cant construct directive usage for @%s: %w", name, err))
 		}
-		directiveExecutables[i] = directiveExecutable
+		directiveExecutables = append(directiveExecutables, directiveExecutable)
 	}
 	return directiveExecutables
 }
@@ -488,6 +816,10 @@ func (agh *Actograph) getType(typeDefinition ast.Type) graphql.Type {
 		return union
 	}
 
+	if iface, isInterface := agh.interfaces[name]; isInterface {
+		return iface
+	}
+
 	if inputObject, isInputObject := agh.inputObjects[name]; isInputObject {
 		return inputObject
 	}
@@ -507,24 +839,68 @@ func (agh *Actograph) getType(typeDefinition ast.Type) graphql.Type {
 func (agh *Actograph) makeEmptyObjects() {
 	// TODO: make sure its necessary
 	for name := range agh.enumDefinitions {
-		agh.enums[name] = nil
+		if !agh.isBackendEnum(name) && !agh.hiddenTypes[name] {
+			agh.enums[name] = nil
+		}
+	}
+
+	// objects are resolved by name in interfaces, so every schema variant captures own objects
+	objects := agh.objects
+	implementations := map[string][]*graphql.Object{}
+	for name, interfaceDefinition := range agh.interfaceDefinitions {
+		if agh.hiddenTypes[name] {
+			continue
+		}
+		var description string
+		if interfaceDefinition.Description != nil {
+			description = interfaceDefinition.Description.Value
+		}
+		interfaceName := name
+		agh.interfaces[name] = graphql.NewInterface(graphql.InterfaceConfig{
+			Name:   name,
+			Fields: graphql.Fields{},
+			ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+				if _, omitted := p.Value.(omittedObject); omitted && len(implementations[interfaceName]) > 0 {
+					return implementations[interfaceName][0]
+				}
+				// nil result is reported by graphql as field error
+				return objects[typenameOf(p.Value)]
+			},
+			Description: description,
+		})
 	}
 
 	for name, objDefinition := range agh.objectDefinitions {
+		if agh.hiddenTypes[name] {
+			continue
+		}
 		var description string
 		if objDefinition.Description != nil {
 			description = objDefinition.Description.Value
 		}
+		interfaces := []*graphql.Interface{}
+		for _, interfaceName := range agh.objectInterfaces(name, objDefinition) {
+			if iface, has := agh.interfaces[interfaceName]; has {
+				interfaces = append(interfaces, iface)
+			}
+		}
 		obj := graphql.NewObject(graphql.ObjectConfig{
 			Name:        name,
+			Interfaces:  interfaces,
 			IsTypeOf:    nil,
 			Fields:      graphql.Fields{},
 			Description: description,
 		})
+		for _, iface := range interfaces {
+			implementations[iface.Name()] = append(implementations[iface.Name()], obj)
+		}
 		agh.objects[name] = obj
 	}
 
 	for name, objDefinition := range agh.inputObjectDefinitions {
+		if agh.hiddenTypes[name] {
+			continue
+		}
 		var description string
 		if objDefinition.Description != nil {
 			description = objDefinition.Description.Value
@@ -538,13 +914,19 @@ func (agh *Actograph) makeEmptyObjects() {
 	}
 
 	for unionName, unionDefinition := range agh.unionDefinitions {
-		unionTypes := make([]*graphql.Object, len(unionDefinition.Types))
-		for i, unionTypeNamed := range unionDefinition.Types {
+		if agh.hiddenTypes[unionName] {
+			continue
+		}
+		unionTypes := make([]*graphql.Object, 0, len(unionDefinition.Types))
+		for _, unionTypeNamed := range unionDefinition.Types {
+			if agh.hiddenTypes[unionTypeNamed.Name.Value] {
+				continue
+			}
 			unionType, hasNamedType := agh.objects[unionTypeNamed.Name.Value]
 			if !hasNamedType {
 				panic("unknown union type (should be Named, and this panic should be more self-explainable)")
 			}
-			unionTypes[i] = unionType
+			unionTypes = append(unionTypes, unionType)
 		}
 		var description string
 		if unionDefinition.Description != nil {
@@ -555,6 +937,9 @@ func (agh *Actograph) makeEmptyObjects() {
 			Types: unionTypes,
 			// TODO: make it configurable
 			ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+				if _, omitted := p.Value.(omittedObject); omitted {
+					return unionTypes[0]
+				}
 				valueMap, ok := p.Value.(map[string]interface{})
 				if !ok {
 					panic("TODO: only map[string]interface{} supported in union type resolver now")
@@ -575,6 +960,30 @@ func (agh *Actograph) makeEmptyObjects() {
 	}
 }
 
+// typenameOf returns name of object type of value resolved for interface type
+func typenameOf(value interface{}) string {
+	switch value := value.(type) {
+	case Typenamer:
+		return value.GraphQLTypename()
+	case map[string]interface{}:
+		typename, _ := value["__typename"].(string)
+		return typename
+	}
+	return ""
+}
+
+// locationString formats location in schema like "schema.graphql:3:1" or "line 3, column 1" for unnamed source
+func locationString(loc *ast.Location) string {
+	if loc == nil {
+		return "unknown location"
+	}
+	sourceLocation := location.GetLocation(loc.Source, loc.Start)
+	if loc.Source != nil && loc.Source.Name != "" {
+		return fmt.Sprintf("%s:%d:%d", loc.Source.Name, sourceLocation.Line, sourceLocation.Column)
+	}
+	return fmt.Sprintf("line %d, column %d", sourceLocation.Line, sourceLocation.Column)
+}
+
 func (agh *Actograph) addDirective(n *ast.DirectiveDefinition) {
 	name := n.Name.Value
 	if _, has := agh.directiveDefinitions[name]; has {
@@ -616,8 +1025,10 @@ func (agh *Actograph) addEnum(node *ast.EnumDefinition) {
 
 func (agh *Actograph) addScalar(node *ast.ScalarDefinition) {
 	// TODO: implement scalar directives when found use cases :)
-	if len(node.Directives) > 0 {
-		panic("directives under scalar is not implemented yet")
+	for _, dir := range node.Directives {
+		if !isHardcodedDirective(dir.Name.Value) {
+			panic("directives under scalar is not implemented yet")
+		}
 	}
 	name := node.Name.Value
 	var description string
@@ -632,13 +1043,17 @@ func (agh *Actograph) addScalar(node *ast.ScalarDefinition) {
 	agh.declaredScalars[name] = ScalarDefinition{
 		Name:        name,
 		Description: description,
+		Directives:  node.Directives,
+		Loc:         node.Loc,
 	}
 }
 
 func (agh *Actograph) addUnion(node *ast.UnionDefinition) {
 	// TODO: implement union directives when found use cases :)
-	if len(node.Directives) > 0 {
-		panic("directives under scalar is not implemented yet")
+	for _, dir := range node.Directives {
+		if !isHardcodedDirective(dir.Name.Value) {
+			panic("directives under union is not implemented yet")
+		}
 	}
 
 	name := node.Name.Value
@@ -650,11 +1065,29 @@ func (agh *Actograph) addUnion(node *ast.UnionDefinition) {
 	agh.unionDefinitions[name] = node
 }
 
+func (agh *Actograph) addInterface(node *ast.InterfaceDefinition) {
+	for _, dir := range node.Directives {
+		if !isHardcodedDirective(dir.Name.Value) {
+			panic("directives under interface is not implemented yet")
+		}
+	}
+
+	name := node.Name.Value
+
+	if _, has := agh.interfaceDefinitions[name]; has {
+		log.Panicf("interface with name '%s' already defined", name)
+	}
+
+	agh.interfaceDefinitions[name] = node
+}
+
 func (agh *Actograph) addExtensionDefinition(node *ast.TypeExtensionDefinition) {
 	name := node.Definition.Name.Value
 	agh.extensionDefinitions[name] = append(agh.extensionDefinitions[name], node)
 }
 
+// executeDirectives executes directives ordered by directive.SortByPhase. When directive returns error,
+// rest of directives are skipped and directive.PhaseOnError directives are executed
 func (agh *Actograph) executeDirectives(
 	ctx context.Context,
 	source interface{},
@@ -663,16 +1096,43 @@ func (agh *Actograph) executeDirectives(
 	directives []directive.Directive,
 ) (interface{}, context.Context, error) {
 	var err error
+	stoppedPhase := directive.Phase(-1)
 	for _, dir := range directives {
+		phase := directive.PhaseOf(dir)
+		if phase == directive.PhaseOnError {
+			break
+		}
+		if phase == stoppedPhase {
+			continue
+		}
 		resolvedValue, ctx, err = dir.Execute(ctx, source, resolvedValue, fieldArgs)
+		if err == directive.ErrOmitField {
+			return nil, ctx, err
+		}
 		if err != nil {
 			if err == directive.ErrStopExecutionWithoutError {
 				err = nil
+				stoppedPhase = phase
+				continue
 			}
 			break
 		}
 	}
 
+	if err == nil {
+		return resolvedValue, ctx, nil
+	}
+
+	for _, dir := range directives {
+		if directive.PhaseOf(dir) != directive.PhaseOnError {
+			continue
+		}
+		resolvedValue, ctx, err = dir.Execute(directive.ContextWithError(ctx, err), source, resolvedValue, fieldArgs)
+		if err == nil || err == directive.ErrStopExecutionWithoutError {
+			return resolvedValue, ctx, nil
+		}
+	}
+
 	return resolvedValue, ctx, err
 }
 
@@ -1,15 +1,33 @@
 package actograph_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/actord/actograph/examples/scalars"
+	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
 
 	"github.com/actord/actograph"
 	"github.com/actord/actograph/directive"
 	"github.com/actord/actograph/examples/directives"
+	standardScalars "github.com/actord/actograph/scalars"
 )
 
 const exampleDirectives = "./examples/schema/directives.graphql"
@@ -21,6 +39,28 @@ const testScalarSchema = "./examples/schema/testScalar.graphql"
 const testEnum = "./examples/schema/testEnum.graphql"
 const testInputType = "./examples/schema/testInputType.graphql"
 const testDefineDirectivesSchema = "./examples/schema/testDefineDirectives.graphql"
+const testLookaheadSchema = "./examples/schema/testLookahead.graphql"
+const testPhasesSchema = "./examples/schema/testPhases.graphql"
+const testTypeDirectivesSchema = "./examples/schema/testTypeDirectives.graphql"
+const testFieldMiddlewareSchema = "./examples/schema/testFieldMiddleware.graphql"
+const testExecutableDirectivesSchema = "./examples/schema/testExecutableDirectives.graphql"
+const testOmitFieldSchema = "./examples/schema/testOmitField.graphql"
+const testEnumPrivacyBackendSchema = "./examples/schema/testEnumPrivacyBackend.graphql"
+const testEnumPrivacyFrontendSchema = "./examples/schema/testEnumPrivacyFrontend.graphql"
+const testVisibilitySchema = "./examples/schema/testVisibility.graphql"
+const testIntrospectionSchema = "./examples/schema/testIntrospection.graphql"
+const testConnectionSchema = "./examples/schema/testConnection.graphql"
+const testNodeSchema = "./examples/schema/testNode.graphql"
+const testScalarsSchema = "./examples/schema/testScalars.graphql"
+const testLegacyScalarSchema = "./examples/schema/testLegacyScalar.graphql"
+const testGoScalarsSchema = "./examples/schema/testGoScalars.graphql"
+const testEnumBindingSchema = "./examples/schema/testEnumBinding.graphql"
+const testDefaultValuesSchema = "./examples/schema/testDefaultValues.graphql"
+const testInvalidDefaultValuesSchema = "./examples/schema/testInvalidDefaultValues.graphql"
+const testExtensionsSchema = "./examples/schema/testExtensions.graphql"
+const testInvalidExtensionsSchema = "./examples/schema/testInvalidExtensions.graphql"
+const testSchemaBuilderSchema = "./examples/schema/testSchemaBuilder.graphql"
+const testResolversSchema = "./examples/schema/testResolvers.graphql"
 
 // Test todo:
 //  - check is Enum definition without @enumPrivacy directive fired error
@@ -36,7 +76,7 @@ func TestErrorUndefinedDirective(t *testing.T) {
 func TestSimpleWorkflow(t *testing.T) {
 	gscm, err := getGQLSchema(simpleSchema)
 	if err != nil {
-		t.Fatalf("error when creating schema: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
 	// we can safely ignore error, because its schema validation error only
@@ -56,7 +96,7 @@ func TestSimpleWorkflow(t *testing.T) {
 func TestExtend(t *testing.T) {
 	gscm, err := getGQLSchema(simpleWithExtendSchema)
 	if err != nil {
-		t.Fatalf("error when creating schema: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
 	// we can safely ignore error, because its schema validation error only
@@ -84,7 +124,7 @@ func TestExtend(t *testing.T) {
 func TestContextWorkflow(t *testing.T) {
 	gscm, err := getGQLSchema(testContextSchema)
 	if err != nil {
-		t.Fatalf("error when creating schema: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
 	// we can safely ignore error, because its schema validation error only
@@ -131,7 +171,7 @@ func TestContextWorkflow(t *testing.T) {
 func TestScalar(t *testing.T) {
 	gscm, err := getGQLSchema(testScalarSchema)
 	if err != nil {
-		t.Fatalf("error when creating schema: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
 	// we can safely ignore error, because its schema validation error only
@@ -174,7 +214,7 @@ func TestScalar(t *testing.T) {
 func TestInputObject(t *testing.T) {
 	gscm, err := getGQLSchema(testInputType)
 	if err != nil {
-		t.Fatalf("error when creating schema: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
 	result, _ := gscm.Do(actograph.RequestQuery{
@@ -226,7 +266,7 @@ func TestDefineDirectives(t *testing.T) {
 	`
 	gscm, err := getGQLSchema(testDefineDirectivesSchema)
 	if err != nil {
-		t.Fatalf("error when creating schema: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
 	result, _ := gscm.Do(actograph.RequestQuery{
@@ -254,7 +294,7 @@ func TestEnum(t *testing.T) {
 	`
 	gscm, err := getGQLSchema(testEnum)
 	if err != nil {
-		t.Fatalf("error when creating schema: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
 	result, _ := gscm.Do(actograph.RequestQuery{
@@ -263,30 +303,1790 @@ func TestEnum(t *testing.T) {
 	log.Println("result", result)
 }
 
-func getGQLSchema(filenames ...string) (*actograph.Actograph, error) {
-	allFiles := append([]string{exampleDirectives}, filenames...)
+func TestLookahead(t *testing.T) {
+	gscm, err := getGQLSchema(testLookaheadSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
 
-	agh, err := actograph.NewActographFiles(allFiles...)
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test($withFriends: Boolean!, $skipName: Boolean!) {
+			user {
+				id
+				userName: name @skip(if: $skipName)
+				... on User { id }
+				...FriendsFragment @include(if: $withFriends)
+				__typename
+				requestedFields
+			}
+		}
+		fragment FriendsFragment on User {
+			friends(after: "cursor") { name }
+		}`,
+		VariableValues: map[string]interface{}{
+			"withFriends": true,
+			"skipName":    true,
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	requested, _ := json.Marshal(result.Data.(map[string]interface{})["user"].(map[string]interface{})["requestedFields"])
+	expected := `["id","friends(after:cursor,first:10)","friends.name","requestedFields"]`
+	if string(requested) != expected {
+		t.Fatalf("requestedFields = %s, expected %s", requested, expected)
+	}
+}
+
+func TestDirectivePhases(t *testing.T) {
+	gscm, err := getGQLSchema(testPhasesSchema)
 	if err != nil {
-		return nil, fmt.Errorf("when parse file: %w", err)
+		t.Fatalf("error when creating schema: %v", err)
 	}
 
-	// RegisterDirectives before parse
-	if err := agh.RegisterDirectives(
-		// todo: move default directives elsewhere
-		directive.NewDirectiveDefinition("deprecated", directive.NewDeprecated),
-		directive.NewDirectiveDefinition("_value", directive.NewValue),
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test { upper fallback }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
 
-		directive.NewDirectiveDefinition("resolveString", directives.NewDirectiveResolveString),
-		directive.NewDirectiveDefinition("resolveArg", directives.NewDirectiveResolveArg),
-		directive.NewDirectiveDefinition("setContext", directives.NewDirectiveSetContext),
-		directive.NewDirectiveDefinition("getContext", directives.NewDirectiveGetContext),
-		directive.NewDirectiveDefinition("expect", directives.NewDirectiveExpect),
-	); err != nil {
-		return nil, fmt.Errorf("when registering directives: %w", err)
+	upper := result.Data.(map[string]interface{})["upper"]
+	if upper != "HELLO" {
+		t.Fatalf("upper = %v, expected HELLO", upper)
 	}
 
-	agh.RegisterScalar(scalars.DoubleStringScalarConfig)
+	fallback := result.Data.(map[string]interface{})["fallback"]
+	if fallback != "fallback (expected string: 'something else' but got: 'resolved')" {
+		t.Fatalf("fallback = %v", fallback)
+	}
+}
+
+func TestTypeDirectives(t *testing.T) {
+	gscm, err := getGQLSchema(testTypeDirectivesSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test { inherited overridden optedOut optedOutPrefix extended }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	expected := map[string]string{
+		"inherited":      "ext:type:schema:a",
+		"overridden":     "field:b",
+		"optedOut":       "c",
+		"optedOutPrefix": "d",
+		"extended":       "ext:type:schema:e",
+	}
+	for field, expectedValue := range expected {
+		if value := result.Data.(map[string]interface{})[field]; value != expectedValue {
+			t.Fatalf("%s = %v, expected %s", field, value, expectedValue)
+		}
+	}
+}
+
+func TestExecutableDirectives(t *testing.T) {
+	gscm, err := getGQLSchema(testExecutableDirectivesSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test($prefix: String!) @prefix(val: "op:") {
+			upper: hello @upper
+			prefixed: hello @prefix(val: $prefix)
+			... @upper {
+				bye
+			}
+			...ByeFragment
+		}
+		fragment ByeFragment on Query {
+			notUpper: bye
+		}`,
+		VariableValues: map[string]interface{}{
+			"prefix": "var:",
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	expected := map[string]string{
+		"upper":    "OP:WORLD",
+		"prefixed": "var:op:world",
+		"bye":      "OP:BYE",
+		"notUpper": "op:bye",
+	}
+	for field, expectedValue := range expected {
+		if value := result.Data.(map[string]interface{})[field]; value != expectedValue {
+			t.Fatalf("%s = %v, expected %s", field, value, expectedValue)
+		}
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test { hello @expect(string: "world") }`,
+	})
+	if len(result.Errors) == 0 {
+		t.Fatalf("type system directive should not be allowed in operation")
+	}
+
+	// directive is executable only in variants where type of its argument is visible
+	agh := actograph.NewActograph()
+	if err := agh.Parse([]byte(`schema { query: Query }
+		enum Volume @visibility(audiences: ["admin"]) { LOUD }
+		directive @shout(volume: Volume) on FIELD
+		type Query { hello: String, offers: String @visibility(audiences: ["partner"]) }`)); err != nil {
+		t.Fatalf("error when parsing schema: %v", err)
+	}
+	if err := agh.RegisterDirectives(directive.NewDirectiveDefinition("shout", directives.NewDirectiveUpper)); err != nil {
+		t.Fatalf("error when registering directives: %v", err)
+	}
+	for _, audience := range []string{"", "admin"} {
+		result, err := agh.Do(actograph.RequestQuery{
+			RequestString: `{ hello @shout(volume: LOUD) }`,
+			RootObject:    map[string]interface{}{"hello": "world"},
+			Audience:      audience,
+		})
+		if err != nil || len(result.Errors) > 0 {
+			t.Fatalf("unexpected errors for audience '%s': %v %v", audience, err, result.Errors)
+		}
+		if hello := result.Data.(map[string]interface{})["hello"]; hello != "WORLD" {
+			t.Fatalf("hello = %v for audience '%s', expected WORLD", hello, audience)
+		}
+	}
+	result, _ = agh.Do(actograph.RequestQuery{RequestString: `{ hello @shout }`, Audience: "partner"})
+	if len(result.Errors) == 0 {
+		t.Fatalf("directive with hidden argument type should not be allowed for audience 'partner'")
+	}
+}
+
+func TestOmitField(t *testing.T) {
+	var mx sync.Mutex
+	var middlewareValues []string
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.UseField(func(next actograph.FieldResolver) actograph.FieldResolver {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				value, err := next(p)
+				mx.Lock()
+				defer mx.Unlock()
+				middlewareValues = append(middlewareValues, fmt.Sprintf("%s=%v/%v", p.Info.FieldName, value, err))
+				return value, err
+			}
+		})
+	}, testOmitFieldSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	query := `query Test {
+		public
+		secretNotes
+		secretUser { name age }
+		user { name age friend { name age } }
+		secretMood
+		secretDouble
+	}`
+	user := map[string]interface{}{
+		"name": "user",
+		"age":  42,
+		"friend": map[string]interface{}{
+			"name": "friend",
+			"age":  24,
+		},
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: query,
+		RootObject: map[string]interface{}{
+			"user": user,
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"public":"public","user":{"friend":{"name":"friend"},"name":"user"}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+	// middlewares don't get placeholders of omitted fields and skip fields of omitted objects
+	sort.Strings(middlewareValues)
+	expectedValues := "age=<nil>/omit field,age=<nil>/omit field,public=public/<nil>,secretDouble=<nil>/omit field," +
+		"secretMood=<nil>/omit field,secretNotes=<nil>/omit field,secretUser=<nil>/omit field"
+	if strings.Join(middlewareValues, ",") != expectedValues {
+		t.Fatalf("middleware values = %v, expected %s", middlewareValues, expectedValues)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: query,
+		RootObject: map[string]interface{}{
+			"owner":        true,
+			"secretUser":   user,
+			"user":         user,
+			"secretMood":   "SAD",
+			"secretDouble": "double",
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ = json.Marshal(result.Data)
+	expected = `{"public":"public","secretDouble":"doubledouble","secretMood":"SAD","secretNotes":"secret",` +
+		`"secretUser":{"age":42,"name":"user"},"user":{"age":42,"friend":{"age":24,"name":"friend"},"name":"user"}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+}
+
+func TestEnumPrivacy(t *testing.T) {
+	if _, err := getGQLSchema(testEnumPrivacyBackendSchema); err == nil {
+		t.Fatalf("backend enum used as argument type should fail validation")
+	}
+	if _, err := getGQLSchema(testEnumPrivacyFrontendSchema); err == nil {
+		t.Fatalf("frontend enum used as directive argument type should fail validation")
+	}
+
+	gscm, err := getGQLSchema(testContextSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ __type(name: "SetContextValueType") { name } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if backendEnum := result.Data.(map[string]interface{})["__type"]; backendEnum != nil {
+		t.Fatalf("backend enum should not be served in schema, got %v", backendEnum)
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	gscm, err := getGQLSchema(testVisibilitySchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ public internal partner search(debug: true) status }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors in full schema: %v", result.Errors)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ public partner search(filter: {query: "q"}) status }`,
+		Audience:      "partner",
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors in partner schema: %v", result.Errors)
+	}
+
+	for _, query := range []string{`{ internal }`, `{ secret { value } }`, `{ search(debug: true) }`} {
+		result, _ = gscm.Do(actograph.RequestQuery{
+			RequestString: query,
+			Audience:      "public",
+		})
+		if len(result.Errors) == 0 {
+			t.Fatalf("query %s should fail for public audience", query)
+		}
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{
+			secret: __type(name: "Secret") { name }
+			status: __type(name: "Status") { enumValues { name } }
+		}`,
+		Audience: "public",
+	})
+	data, _ := json.Marshal(result.Data)
+	expected := `{"secret":null,"status":{"enumValues":[{"name":"ACTIVE"}]}}`
+	if string(data) != expected {
+		t.Fatalf("introspection = %s, expected %s", data, expected)
+	}
+
+	// hidden implementation of interface is not possible type of interface
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{
+			account { __typename name }
+			accountType: __type(name: "Account") { possibleTypes { name } }
+		}`,
+		RootObject: map[string]interface{}{"account": map[string]interface{}{"__typename": "Customer", "name": "customer"}},
+		Audience:   "public",
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ = json.Marshal(result.Data)
+	expected = `{"account":{"__typename":"Customer","name":"customer"},"accountType":{"possibleTypes":[{"name":"Customer"}]}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	// types reachable only through hidden fields are not served
+	unreachableQuery := `{
+		auditEvent: __type(name: "AuditEvent") { name }
+		loginEvent: __type(name: "LoginEvent") { name }
+	}`
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: unreachableQuery,
+		Audience:      "public",
+	})
+	data, _ = json.Marshal(result.Data)
+	expected = `{"auditEvent":null,"loginEvent":null}`
+	if string(data) != expected {
+		t.Fatalf("introspection = %s, expected %s", data, expected)
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: unreachableQuery,
+		Audience:      "internal",
+	})
+	data, _ = json.Marshal(result.Data)
+	expected = `{"auditEvent":{"name":"AuditEvent"},"loginEvent":{"name":"LoginEvent"}}`
+	if string(data) != expected {
+		t.Fatalf("introspection = %s, expected %s", data, expected)
+	}
+
+	server := httptest.NewServer(gscm.Handler(actograph.HandlerConfig{
+		Audience: func(r *http.Request) string {
+			return r.Header.Get("X-Audience")
+		},
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"query": "{ partner }"}`))
+	req.Header.Set("X-Audience", "public")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var httpResult actograph.Result
+	if err := json.NewDecoder(resp.Body).Decode(&httpResult); err != nil {
+		t.Fatalf("when decoding response: %v", err)
+	}
+	if len(httpResult.Errors) == 0 {
+		t.Fatalf("partner field should be unknown for public audience")
+	}
+
+	// variants are made once, concurrent requests of listed and unknown audiences only read them
+	audienceResults := make(chan []byte)
+	for i := 0; i < 8; i++ {
+		audience := []string{"internal", "partner", "unknown-1", "unknown-2"}[i%4]
+		go func() {
+			result, _ := gscm.Do(actograph.RequestQuery{RequestString: `{ public }`, Audience: audience})
+			data, _ := json.Marshal(result.Data)
+			audienceResults <- data
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if data := <-audienceResults; string(data) != `{"public":"public"}` {
+			t.Fatalf("data = %s, expected public field for every audience", data)
+		}
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	gscm, err := getGQLSchema(testIntrospectionSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ public internal internalType { value } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("internal elements should be queryable: %v", result.Errors)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{
+			query: __type(name: "Query") { fields { name } }
+			internalType: __type(name: "InternalType") { name }
+			account: __type(name: "Account") { possibleTypes { name } }
+		}`,
+	})
+	data, _ := json.Marshal(result.Data)
+	expected := `{"account":{"possibleTypes":[{"name":"Customer"}]},"internalType":null,` +
+		`"query":{"fields":[{"name":"account"},{"name":"public"}]}}`
+	if string(data) != expected {
+		t.Fatalf("introspection = %s, expected %s", data, expected)
+	}
+
+	// introspection mixed with other fields is executed on schema without @internal elements
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{
+			public
+			internal
+			...Types
+			... on Query { __typename internalType { value } }
+		}
+		fragment Types on Query {
+			query: __type(name: "Query") { fields { name } }
+			internalTypeInfo: __type(name: "InternalType") { name }
+		}`,
+		RootObject: map[string]interface{}{"internalType": map[string]interface{}{"value": "value"}},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ = json.Marshal(result.Data)
+	expected = `{"__typename":"Query","internal":"internal","internalType":{"value":"value"},"internalTypeInfo":null,` +
+		`"public":"public","query":{"fields":[{"name":"account"},{"name":"public"}]}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	// introspection settings can't be changed after schema is built
+	gscm, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.SetIntrospectionPredicate(func(ctx context.Context) bool {
+			return ctx.Value("admin") != nil
+		})
+	}, testIntrospectionSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ __schema { queryType { name } } }`,
+	})
+	if len(result.Errors) == 0 {
+		t.Fatalf("introspection should be disallowed")
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ __schema { queryType { name } } }`,
+		Context:       context.WithValue(context.Background(), "admin", true),
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("introspection should be allowed by predicate: %v", result.Errors)
+	}
+
+	gscm, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.DisableIntrospection()
+	}, testIntrospectionSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ __type(name: "Query") { name } }`,
+		Context:       context.WithValue(context.Background(), "admin", true),
+	})
+	if len(result.Errors) == 0 {
+		t.Fatalf("introspection should be disabled")
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ __typename public }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("__typename should be allowed: %v", result.Errors)
+	}
+}
+
+func TestConnection(t *testing.T) {
+	gscm, err := getGQLSchema(testConnectionSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	rootObject := map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"name": "user0"},
+			map[string]interface{}{"name": "user1"},
+			map[string]interface{}{"name": "user2"},
+			map[string]interface{}{"name": "user3"},
+		},
+	}
+	query := `query Test($after: String) {
+		users(first: 2, after: $after) {
+			edges { node { name } }
+			pageInfo { hasNextPage hasPreviousPage endCursor }
+		}
+	}`
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: query,
+		RootObject:    rootObject,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"users":{"edges":[{"node":{"name":"user0"}},{"node":{"name":"user1"}}],` +
+		`"pageInfo":{"endCursor":"Y3Vyc29yOjE=","hasNextPage":true,"hasPreviousPage":false}}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString:  query,
+		RootObject:     rootObject,
+		VariableValues: map[string]interface{}{"after": "Y3Vyc29yOjE="},
+	})
+	data, _ = json.Marshal(result.Data)
+	expected = `{"users":{"edges":[{"node":{"name":"user2"}},{"node":{"name":"user3"}}],` +
+		`"pageInfo":{"endCursor":"Y3Vyc29yOjM=","hasNextPage":false,"hasPreviousPage":true}}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ numbers(first: 3, after: "Y3Vyc29yOjk=") { edges { node cursor } pageInfo { hasNextPage } } }`,
+	})
+	data, _ = json.Marshal(result.Data)
+	expected = `{"numbers":{"edges":[{"cursor":"Y3Vyc29yOjEw","node":10},{"cursor":"Y3Vyc29yOjEx","node":11},` +
+		`{"cursor":"Y3Vyc29yOjEy","node":12}],"pageInfo":{"hasNextPage":true}}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+}
+
+func TestNode(t *testing.T) {
+	if _, err := getGQLSchema(testNodeSchema); err == nil {
+		t.Fatal("expected error for types marked by @node without fetchers")
+	}
+
+	posts := map[string]interface{}{
+		"1": map[string]interface{}{"id": "1", "title": "first"},
+	}
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		if err := agh.RegisterNodeFetcher("User", func(ctx context.Context, id string) (interface{}, error) {
+			return map[string]interface{}{"id": id, "name": "user" + id}, nil
+		}); err != nil {
+			return err
+		}
+		return agh.RegisterNodeFetcher("Post", func(ctx context.Context, id string) (interface{}, error) {
+			return posts[id], nil
+		})
+	}, testNodeSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ users { id } }`,
+		RootObject: map[string]interface{}{
+			"users": []interface{}{map[string]interface{}{"id": 7, "name": "user7"}},
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	userID := directive.EncodeGlobalID("User", "7")
+	data, _ := json.Marshal(result.Data)
+	if expected := `{"users":[{"id":"` + userID + `"}]}`; string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	postID := directive.EncodeGlobalID("Post", "1")
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test($id: ID!, $ids: [ID!]!) {
+			node(id: $id) { id ... on User { name } }
+			nodes(ids: $ids) { __typename id ... on Post { title } }
+		}`,
+		VariableValues: map[string]interface{}{
+			"id":  userID,
+			"ids": []interface{}{postID, directive.EncodeGlobalID("Post", "2"), directive.EncodeGlobalID("Unknown", "1")},
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ = json.Marshal(result.Data)
+	expected := `{"node":{"id":"` + userID + `","name":"user7"},` +
+		`"nodes":[{"__typename":"Post","id":"` + postID + `","title":"first"},null,null]}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ node(id: "not a global id") { id } }`,
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected error for invalid global id, got %v", result.Errors)
+	}
+
+	typeName, id, err := directive.DecodeGlobalID(userID)
+	if err != nil || typeName != "User" || id != "7" {
+		t.Fatalf("DecodeGlobalID = %s, %s, %v", typeName, id, err)
+	}
+}
+
+func TestStandardScalars(t *testing.T) {
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.RegisterScalarsE(standardScalars.All()...)
+	}, testScalarsSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test($bigInt: BigInt!, $duration: Duration!) {
+			json(arg: {a: [1, 2.5, "s", true], b: {c: null_enum}})
+			uuid(arg: "123E4567-E89B-12D3-A456-426614174000")
+			email(arg: "user@example.com")
+			url(arg: "https://example.com/path?q=1")
+			bigInt(arg: $bigInt)
+			literalBigInt: bigInt(arg: 12345678901234567890)
+			decimal(arg: 12.50)
+			date(arg: "2006-01-02")
+			time(arg: "15:04:05.5")
+			duration(arg: $duration)
+		}`,
+		VariableValues: map[string]interface{}{
+			"bigInt":   "123456789012345678901234567890",
+			"duration": "P1DT1H30M",
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"bigInt":"123456789012345678901234567890","date":"2006-01-02","decimal":"12.50",` +
+		`"duration":"P1DT1H30M","email":"user@example.com","json":{"a":[1,2.5,"s",true],"b":{"c":"null_enum"}},` +
+		`"literalBigInt":"12345678901234567890","time":"15:04:05.5","url":"https://example.com/path?q=1",` +
+		`"uuid":"123e4567-e89b-12d3-a456-426614174000"}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	for _, query := range []string{
+		`{ uuid(arg: "not-uuid") }`,
+		`{ email(arg: "User <user@example.com>") }`,
+		`{ url(arg: "/relative") }`,
+		`{ bigInt(arg: 1.5) }`,
+		`{ decimal(arg: "1e5") }`,
+		`{ date(arg: "2006-13-01") }`,
+		`{ duration(arg: "P1Y") }`,
+	} {
+		result, _ = gscm.Do(actograph.RequestQuery{RequestString: query})
+		if len(result.Errors) == 0 {
+			t.Fatalf("expected error for %s", query)
+		}
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString:  `query Test($arg: Time!) { time(arg: $arg) }`,
+		VariableValues: map[string]interface{}{"arg": "25:00:00"},
+	})
+	if len(result.Errors) == 0 {
+		t.Fatalf("expected error for invalid variable")
+	}
+
+	exe, err := gscm.Build()
+	if err != nil {
+		t.Fatalf("error when building schema: %v", err)
+	}
+	for scalarName, expected := range map[string]string{
+		"Date": "https://www.rfc-editor.org/rfc/rfc3339#section-5.6",
+		"UUID": "https://example.com/uuid",
+	} {
+		if url, _ := exe.SpecifiedByURL(scalarName); url != expected {
+			t.Fatalf("specifiedBy URL of %s = %s, expected %s", scalarName, url, expected)
+		}
+	}
+}
+
+func TestSpecifiedByPerActograph(t *testing.T) {
+	specifiedByURLOf := func(schema string) string {
+		agh := actograph.NewActograph()
+		if err := agh.Parse([]byte(schema)); err != nil {
+			t.Fatalf("error when parsing schema: %v", err)
+		}
+		exe, err := agh.Build()
+		if err != nil {
+			t.Fatalf("error when building schema: %v", err)
+		}
+		url, _ := exe.SpecifiedByURL("DateTime")
+		return url
+	}
+
+	// graphql.DateTime is shared by actographs, URL of one actograph should not leak to another
+	withURL := specifiedByURLOf(`schema { query: Query } scalar DateTime @specifiedBy(url: "https://example.com/a") type Query { now: DateTime }`)
+	if expected := "https://example.com/a"; withURL != expected {
+		t.Fatalf("URL = %s, expected %s", withURL, expected)
+	}
+	if withoutURL := specifiedByURLOf(`schema { query: Query } type Query { now: DateTime }`); withoutURL != "" {
+		t.Fatalf("URL = %s, expected none", withoutURL)
+	}
+
+	// introspection types of graphql-go are not changed
+	if _, has := graphql.TypeType.Fields()["specifiedByURL"]; has {
+		t.Fatalf("__Type.specifiedByURL should not be added to graphql-go introspection")
+	}
+}
+
+func TestScalarErrors(t *testing.T) {
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.RegisterScalarsE(standardScalars.All()...)
+	}, testScalarsSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ uuid(arg: "not-uuid") }`,
+	})
+	if len(result.Errors) != 1 || len(result.Errors[0].Locations) != 1 ||
+		!strings.Contains(result.Errors[0].Message, `invalid UUID "not-uuid"`) {
+		t.Fatalf("unexpected errors for invalid literal: %v", result.Errors)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString:  `query Test($arg: Time!) { time(arg: $arg) }`,
+		VariableValues: map[string]interface{}{"arg": "25:00:00"},
+	})
+	if len(result.Errors) != 1 || len(result.Errors[0].Locations) != 1 ||
+		!strings.Contains(result.Errors[0].Message, `Expected type "Time", found "25:00:00": invalid value`) {
+		t.Fatalf("unexpected errors for invalid variable: %v", result.Errors)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ invalidUUID }`,
+	})
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "UUID cannot represent value not-uuid") {
+		t.Fatalf("unexpected errors for invalid resolved value: %v", result.Errors)
+	}
+
+	// scalars made by RegisterScalar are passed to graphql as is, nil is null or invalid value
+	gscm, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.RegisterScalar(actograph.ScalarConfig{
+			Name: "Legacy",
+			Serialize: func(value interface{}) interface{} {
+				return nil
+			},
+			ParseValue: func(value interface{}) interface{} {
+				return nil
+			},
+			ParseLiteral: func(valueAST ast.Value) interface{} {
+				return nil
+			},
+		})
+	}, testLegacyScalarSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ serializeValue }`,
+	})
+	data, _ := json.Marshal(result.Data)
+	if len(result.Errors) > 0 || string(data) != `{"serializeValue":null}` {
+		t.Fatalf("unexpected result for nil serialized by legacy scalar: %s, %v", data, result.Errors)
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ parse(arg: "value") }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != `Argument "arg" has invalid value "value".
+Expected type "Legacy", found "value".` {
+		t.Fatalf("unexpected errors for nil parsed by legacy scalar: %v", result.Errors)
+	}
+}
+
+// TestValidationRules fails when graphql-go changes its rule set, actograph copies it without literal rules
+func TestValidationRules(t *testing.T) {
+	expected := []graphql.ValidationRuleFn{
+		graphql.ArgumentsOfCorrectTypeRule,
+		graphql.DefaultValuesOfCorrectTypeRule,
+		graphql.FieldsOnCorrectTypeRule,
+		graphql.FragmentsOnCompositeTypesRule,
+		graphql.KnownArgumentNamesRule,
+		graphql.KnownDirectivesRule,
+		graphql.KnownFragmentNamesRule,
+		graphql.KnownTypeNamesRule,
+		graphql.LoneAnonymousOperationRule,
+		graphql.NoFragmentCyclesRule,
+		graphql.NoUndefinedVariablesRule,
+		graphql.NoUnusedFragmentsRule,
+		graphql.NoUnusedVariablesRule,
+		graphql.OverlappingFieldsCanBeMergedRule,
+		graphql.PossibleFragmentSpreadsRule,
+		graphql.ProvidedNonNullArgumentsRule,
+		graphql.ScalarLeafsRule,
+		graphql.UniqueArgumentNamesRule,
+		graphql.UniqueFragmentNamesRule,
+		graphql.UniqueInputFieldNamesRule,
+		graphql.UniqueOperationNamesRule,
+		graphql.UniqueVariableNamesRule,
+		graphql.VariablesAreInputTypesRule,
+		graphql.VariablesInAllowedPositionRule,
+	}
+	if len(graphql.SpecifiedRules) != len(expected) {
+		t.Fatalf("graphql.SpecifiedRules has %d rules, expected %d", len(graphql.SpecifiedRules), len(expected))
+	}
+	for i, rule := range graphql.SpecifiedRules {
+		if reflect.ValueOf(rule).Pointer() != reflect.ValueOf(expected[i]).Pointer() {
+			t.Fatalf("graphql.SpecifiedRules[%d] changed", i)
+		}
+	}
+
+	// rules other than literal rules still apply
+	gscm, err := getGQLSchema(testScalarSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test($unused: String) { parse(arg: "a", arg: "b") }`,
+	})
+	if len(result.Errors) != 2 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestGoScalars(t *testing.T) {
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		if err := actograph.RegisterGoScalar[scalars.Money](agh, "Money"); err != nil {
+			return err
+		}
+		return actograph.RegisterGoScalar[scalars.Point](agh, "Point")
+	}, testGoScalarsSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	// arguments are passed to directives as Money and Point, otherwise they can't be serialized
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `query Test($money: Money!, $point: Point!) {
+			literalMoney: money(arg: "12.5 USD")
+			variableMoney: money(arg: $money)
+			literalPoint: point(arg: [1, 2.5])
+			variablePoint: point(arg: $point)
+		}`,
+		VariableValues: map[string]interface{}{
+			"money": "-0.99 EUR",
+			"point": []interface{}{3.0, 4.0},
+		},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"literalMoney":"12.50 USD","literalPoint":[1,2.5],"variableMoney":"-0.99 EUR","variablePoint":[3,4]}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ money(arg: "12.555 USD") point(arg: [1]) }`,
+	})
+	if len(result.Errors) != 2 || !strings.Contains(result.Errors[0].Message, "at most 2 fraction digits allowed") {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	if err := actograph.RegisterGoScalar[struct{}](actograph.NewActograph(), "Empty"); err == nil {
+		t.Fatalf("expected error for type without marshalers")
+	}
+}
+
+func TestScalarRegistration(t *testing.T) {
+	_, err := getGQLSchema(testScalarsSchema)
+	if err == nil || !strings.Contains(err.Error(), "was declared in schema at "+testScalarsSchema+":") {
+		t.Fatalf("expected error for declared but not registered scalar, got %v", err)
+	}
+
+	gscm, err := getGQLSchema(simpleSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	warnings := gscm.Warnings()
+	if len(warnings) != 1 || warnings[0] != "scalar 'DoubleString' is registered, but not declared in schema" {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	if err := gscm.RegisterScalar(scalars.DoubleStringScalarConfig); err == nil {
+		t.Fatalf("expected error for duplicated scalar")
+	}
+	uuid := standardScalars.UUID
+	uuid.Name = "ID"
+	if err := gscm.RegisterScalarE(uuid); err == nil {
+		t.Fatalf("expected error for overriding builtin scalar")
+	}
+	if err := gscm.AllowBuiltinScalarOverride(); err != nil {
+		t.Fatalf("unexpected error when allowing override of builtin scalars: %v", err)
+	}
+	if err := gscm.RegisterScalarE(uuid); err != nil {
+		t.Fatalf("unexpected error for allowed override of builtin scalar: %v", err)
+	}
+}
+
+type status int
+
+const (
+	statusActive status = iota + 1
+	statusBlocked
+)
+
+func TestEnumBinding(t *testing.T) {
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		if err := actograph.BindEnum(agh, "Status", map[string]status{"ACTIVE": statusActive}); err == nil {
+			return fmt.Errorf("expected error for not bound enum value")
+		}
+		return actograph.BindEnum(agh, "Status", map[string]status{"ACTIVE": statusActive, "BLOCKED": statusBlocked})
+	}, testEnumBindingSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	// argument is passed as status, otherwise it can't be serialized
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString:  `query Test($status: Status!) { literal: status(arg: BLOCKED) variable: status(arg: $status) statuses }`,
+		VariableValues: map[string]interface{}{"status": "ACTIVE"},
+		RootObject:     map[string]interface{}{"statuses": []status{statusBlocked, statusActive}},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"literal":"BLOCKED","statuses":["BLOCKED","ACTIVE"],"variable":"ACTIVE"}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ unknownStatus }`,
+		RootObject:    map[string]interface{}{"unknownStatus": status(42)},
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "enum 'Status' cannot represent value 42 of type actograph_test.status" {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestDefaultValues(t *testing.T) {
+	gscm, err := getGQLSchema(testDefaultValuesSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ filter { tags order page { size offset } } order tags double }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"double":"abababab","filter":{"order":"ASC","page":{"offset":0,"size":10},"tags":["new"]},"order":"DESC","tags":["single"]}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	_, err = getGQLSchema(testInvalidDefaultValuesSchema)
+	if err == nil {
+		t.Fatalf("expected error for invalid default values")
+	}
+	for _, expected := range []string{
+		`has invalid default value UP: Expected type "Order", found UP.`,
+		`has invalid default value "ten": Expected type "Int", found "ten".`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("error %q should contain %q", err, expected)
+		}
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	gscm, err := getGQLSchema(testExtensionsSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{
+			filter { name tag }
+			order
+			double
+			item: __type(name: "Item") { possibleTypes { name } }
+			named { title ... on Movie { name } }
+		}`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"double":"abab","filter":{"name":"all","tag":"new"},` +
+		`"item":{"possibleTypes":[{"name":"Book"},{"name":"Movie"}]},` +
+		`"named":null,"order":"DESC"}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+	exe, err := gscm.Build()
+	if err != nil {
+		t.Fatalf("error when building schema: %v", err)
+	}
+	if url, _ := exe.SpecifiedByURL("DoubleString"); url != "https://example.com/double-string" {
+		t.Fatalf("specifiedBy URL of extended scalar = %s", url)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{RequestString: `mutation { echo(arg: "hi") }`})
+	data, _ = json.Marshal(result)
+	if expected := `{"data":{"echo":"echo:hi"}}`; string(data) != expected {
+		t.Fatalf("result = %s, expected %s", data, expected)
+	}
+
+	_, err = getGQLSchema(testInvalidExtensionsSchema)
+	if err == nil {
+		t.Fatalf("expected error for invalid extensions")
+	}
+	for _, expected := range []string{
+		`enum 'Order' extended at ` + testInvalidExtensionsSchema + `:13:8: value 'ASC' is already defined`,
+		`input 'Missing' extended at ` + testInvalidExtensionsSchema + `:17:8 is not defined`,
+		`type 'Query' extended at ` + testInvalidExtensionsSchema + `:21:1: field 'order' is already defined`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("error %q should contain %q", err, expected)
+		}
+	}
+}
+
+func TestNewActographFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		// file without trailing newline ends with comment
+		"schema/schema.graphql":            {Data: []byte("schema {\n    query: Query\n}\n# query type is defined by teams")},
+		"schema/teams/users/query.graphql": {Data: []byte("type Query {\n    user: String\n}\n")},
+		"schema/teams/posts.graphql":       {Data: []byte("extend type Query {\n    post: String\n}\n")},
+		"schema/teams/ignored.txt":         {Data: []byte("not a schema")},
+	}
+
+	agh, err := actograph.NewActographFS(fsys, "schema/schema.graphql", "schema/**/*.graphql")
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ := agh.Do(actograph.RequestQuery{RequestString: `{ user post }`})
+	data, _ := json.Marshal(result)
+	if expected := `{"data":{"post":null,"user":null}}`; string(data) != expected {
+		t.Fatalf("result = %s, expected %s", data, expected)
+	}
+
+	if _, err := actograph.NewActographFS(fsys, "schema/*.gql"); err == nil {
+		t.Fatalf("expected error for pattern without files")
+	}
+
+	fsys["schema/teams/posts.graphql"] = &fstest.MapFile{Data: []byte("extend type Query {\n    user: String\n}\n")}
+	agh, err = actograph.NewActographFS(fsys, "schema/**/*.graphql")
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	expected := "type 'Query' extended at schema/teams/posts.graphql:1:1: field 'user' is already defined"
+	if err := agh.Validate(); err == nil || err.Error() != expected {
+		t.Fatalf("error = %v, expected %s", err, expected)
+	}
+
+	fsys["schema/teams/posts.graphql"] = &fstest.MapFile{Data: []byte("extend type Query {\n    post String\n}\n")}
+	_, err = actograph.NewActographFS(fsys, "schema/**/*.graphql")
+	if err == nil || !strings.Contains(err.Error(), "Syntax Error schema/teams/posts.graphql (2:10)") {
+		t.Fatalf("expected syntax error with file location, got %v", err)
+	}
+}
+
+func TestReloader(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "schema.graphql")
+	writeSchema := func(schema string) {
+		if err := os.WriteFile(schemaFile, []byte(schema), 0o600); err != nil {
+			t.Fatalf("error when writing schema: %v", err)
+		}
+	}
+	helloSchema := func(val string) string {
+		return "directive @resolveString(val: String!) on FIELD_DEFINITION\n" +
+			fmt.Sprintf("schema { query: Query }\ntype Query { hello: String @resolveString(val: %q) }\n", val)
+	}
+	hello := func(do func(request actograph.RequestQuery) (*actograph.Result, error)) string {
+		result, err := do(actograph.RequestQuery{RequestString: `{ hello }`})
+		if err != nil || len(result.Errors) > 0 {
+			t.Fatalf("unexpected errors: %v %v", err, result.Errors)
+		}
+		return result.Data.(map[string]interface{})["hello"].(string)
+	}
+
+	writeSchema(helloSchema("v1"))
+	agh, err := actograph.NewActographFiles(schemaFile)
+	if err != nil {
+		t.Fatalf("error when parsing schema: %v", err)
+	}
+	if err := agh.RegisterDirective(directive.NewDirectiveDefinition("resolveString", directives.NewDirectiveResolveString)); err != nil {
+		t.Fatalf("error when registering directive: %v", err)
+	}
+	reloader, err := actograph.NewReloader(agh)
+	if err != nil {
+		t.Fatalf("error when creating reloader: %v", err)
+	}
+	if reloaded, err := reloader.Reload(); reloaded || err != nil {
+		t.Fatalf("nothing should be reloaded, got %v %v", reloaded, err)
+	}
+
+	// invalid schemas are reported once and not served
+	for _, invalidSchema := range []string{
+		"schema { query: Query }\ntype Query { hello: Unknown }\n",
+		helloSchema("v1") + "type Query { hello: String }\n",
+	} {
+		writeSchema(invalidSchema)
+		if reloaded, err := reloader.Reload(); reloaded || err == nil {
+			t.Fatalf("expected error for invalid schema, got %v %v", reloaded, err)
+		}
+		if reloaded, err := reloader.Reload(); reloaded || err != nil {
+			t.Fatalf("error should be reported once, got %v %v", reloaded, err)
+		}
+		if val := hello(reloader.Do); val != "v1" {
+			t.Fatalf("hello = %s, expected v1", val)
+		}
+	}
+
+	writeSchema(helloSchema("v2"))
+	if reloaded, err := reloader.Reload(); !reloaded || err != nil {
+		t.Fatalf("schema should be reloaded, got %v %v", reloaded, err)
+	}
+	if val := hello(reloader.Do); val != "v2" {
+		t.Fatalf("hello = %s, expected v2", val)
+	}
+	// previous actograph still serves requests started before reload
+	if val := hello(agh.Do); val != "v1" {
+		t.Fatalf("hello = %s, expected v1", val)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reports := make(chan error, 1)
+	go reloader.Watch(ctx, 10*time.Millisecond, func(err error) {
+		reports <- err
+	})
+	writeSchema(helloSchema("v3"))
+	select {
+	case err := <-reports:
+		if err != nil {
+			t.Fatalf("unexpected error when reloading: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("schema was not reloaded")
+	}
+
+	server := httptest.NewServer(reloader.Handler(actograph.HandlerConfig{}))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "?query=" + url.QueryEscape(`{ hello }`))
+	if err != nil {
+		t.Fatalf("error when sending request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if expected := `{"data":{"hello":"v3"}}`; strings.TrimSpace(string(body)) != expected {
+		t.Fatalf("body = %s, expected %s", body, expected)
+	}
+}
+
+type testVersionResolver struct {
+	version string
+}
+
+func (r testVersionResolver) Version() string {
+	return r.version
+}
+
+func TestReloaderRegistrations(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "schema.graphql")
+	writeSchema := func(schema string) {
+		if err := os.WriteFile(schemaFile, []byte(schema), 0o600); err != nil {
+			t.Fatalf("error when writing schema: %v", err)
+		}
+	}
+	schema := "schema { query: Query }\ntype Query { greeting: String version: String }\n"
+	writeSchema(schema)
+	agh, err := actograph.NewActographFiles(schemaFile)
+	if err != nil {
+		t.Fatalf("error when parsing schema: %v", err)
+	}
+	if err := agh.RegisterResolver("Query.greeting", func() string { return "hello" }); err != nil {
+		t.Fatalf("error when registering resolver: %v", err)
+	}
+	if err := agh.BindType("Query", testVersionResolver{version: "1"}); err != nil {
+		t.Fatalf("error when binding type: %v", err)
+	}
+	if err := agh.Use(func(next actograph.Handler) actograph.Handler {
+		return func(ctx context.Context, request actograph.RequestQuery) *actograph.Result {
+			result := next(ctx, request)
+			result.Extensions = map[string]interface{}{"middleware": true}
+			return result
+		}
+	}); err != nil {
+		t.Fatalf("error when registering middleware: %v", err)
+	}
+	if err := agh.UseField(func(next actograph.FieldResolver) actograph.FieldResolver {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			value, err := next(p)
+			return fmt.Sprintf("%v!", value), err
+		}
+	}); err != nil {
+		t.Fatalf("error when registering field middleware: %v", err)
+	}
+	reloader, err := actograph.NewReloader(agh)
+	if err != nil {
+		t.Fatalf("error when creating reloader: %v", err)
+	}
+
+	writeSchema(schema + "# changed\n")
+	if reloaded, err := reloader.Reload(); !reloaded || err != nil {
+		t.Fatalf("schema should be reloaded, got %v %v", reloaded, err)
+	}
+	result, err := reloader.Do(actograph.RequestQuery{RequestString: `{ greeting version }`})
+	if err != nil || len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v %v", err, result.Errors)
+	}
+	data, _ := json.Marshal(result)
+	expected := `{"data":{"greeting":"hello!","version":"1!"},"extensions":{"middleware":true}}`
+	if string(data) != expected {
+		t.Fatalf("result = %s, expected %s", data, expected)
+	}
+}
+
+func TestConcurrentDo(t *testing.T) {
+	gscm, err := getGQLSchema(testVisibilitySchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	requests := []struct {
+		request actograph.RequestQuery
+		valid   bool
+	}{
+		{actograph.RequestQuery{RequestString: `{ public internal partner search(debug: true) status }`}, true},
+		{actograph.RequestQuery{RequestString: `{ public partner status }`, Audience: "partner"}, true},
+		{actograph.RequestQuery{RequestString: `{ internal }`, Audience: "public"}, false},
+		{actograph.RequestQuery{RequestString: `{ internal }`, Audience: "unknown"}, false},
+		{actograph.RequestQuery{RequestString: `{ __type(name: "Status") { enumValues { name } } }`, Audience: "public"}, true},
+	}
+
+	// the first requests build schema concurrently
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for g := 0; g < 16; g++ {
+		g := g
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				r := requests[(g+i)%len(requests)]
+				result, err := gscm.Do(r.request)
+				if err != nil || (len(result.Errors) == 0) != r.valid {
+					errs <- fmt.Errorf("unexpected result of %s for audience '%s': %v %v",
+						r.request.RequestString, r.request.Audience, err, result)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	exe, err := gscm.Build()
+	if err != nil {
+		t.Fatalf("error when building schema: %v", err)
+	}
+	if again, _ := gscm.Build(); again != exe {
+		t.Fatalf("Build should return the same executable")
+	}
+	if err := gscm.RegisterDirective(directive.NewDirectiveDefinition("upper", directives.NewDirectiveUpper)); err == nil {
+		t.Fatalf("expected error when registering directive after build")
+	}
+	if err := gscm.Parse([]byte("type Other { value: String }")); err == nil {
+		t.Fatalf("expected error when parsing schema after build")
+	}
+	if err := gscm.AllowBuiltinScalarOverride(); err == nil {
+		t.Fatalf("expected error when allowing override of builtin scalars after build")
+	}
+	if err := gscm.DisableIntrospection(); err == nil {
+		t.Fatalf("expected error when disabling introspection after build")
+	}
+}
+
+func TestSchemaBuilder(t *testing.T) {
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.Add(
+			actograph.NewEnum("Role").Values("ADMIN", "USER"),
+			actograph.NewInput("UserFilter").Field(
+				actograph.NewInputValue("role", "Role").Default(actograph.EnumLiteral("USER")),
+				actograph.NewInputValue("names", "[String!]").Default([]string{"alice"}),
+			),
+			actograph.NewObject("UserFilterValue").Field(
+				actograph.NewField("role", "Role"),
+				actograph.NewField("names", "[String!]"),
+			),
+			actograph.NewObject("User").Description("user of service").Field(
+				actograph.NewField("name", "String!").Directive("resolveString", map[string]interface{}{"val": "alice"}),
+			),
+			actograph.ExtendObject("Query").Field(
+				actograph.NewField("user", "User!").Directive("resolveString", map[string]interface{}{"val": ""}),
+				actograph.NewField("users", "UserFilterValue").
+					Argument(actograph.NewInputValue("filter", "UserFilter").Default(map[string]interface{}{
+						"role": actograph.EnumLiteral("ADMIN"),
+					})).
+					Directive("resolveArg", map[string]interface{}{"argName": "filter"}),
+				actograph.NewField("role", "Role").
+					Argument(actograph.NewInputValue("role", "Role!")).
+					Directive("resolveArg", map[string]interface{}{"argName": "role"}),
+			),
+		)
+	}, testSchemaBuilderSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ version user { name } users { role names } role(role: GUEST) }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"role":"GUEST","user":{"name":"alice"},"users":{"names":["alice"],"role":"ADMIN"},"version":"1"}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	for _, tt := range []struct {
+		builder  actograph.DefinitionBuilder
+		expected string
+	}{
+		{actograph.NewObject("Query"), "object with name 'Query' already defined"},
+		{actograph.NewObject("Broken").Field(actograph.NewField("name", "[String")), `type Broken: field name: invalid type "[String"`},
+		{actograph.NewInput("Broken").Field(actograph.NewInputValue("name", "String").Default(nil)), "null literals are not supported"},
+	} {
+		agh := actograph.NewActograph()
+		if err := agh.Parse([]byte(`type Query { version: String }`)); err != nil {
+			t.Fatalf("error when parsing schema: %v", err)
+		}
+		err := agh.Add(actograph.NewObject("Added"), tt.builder)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Fatalf("error = %v, expected to contain %q", err, tt.expected)
+		}
+		// failed Add adds nothing
+		if err := agh.Add(actograph.NewObject("Added")); err != nil {
+			t.Fatalf("unexpected error after failed add: %v", err)
+		}
+	}
+	agh := actograph.NewActograph()
+	err = agh.Add(actograph.NewEnum("Role").Values("ADMIN"), actograph.NewEnum("Role").Values("USER"))
+	if err == nil || !strings.Contains(err.Error(), "enum with name 'Role' already defined") {
+		t.Fatalf("error = %v, expected duplicated enum", err)
+	}
+}
+
+type testUser struct {
+	ID   string
+	Name string
+	Role string
+}
+
+type testUsersArgs struct {
+	Filter *struct {
+		Prefix string
+		Roles  []string `graphql:"roles"`
+	}
+}
+
+func TestResolvers(t *testing.T) {
+	users := []*testUser{{ID: "1", Name: "user-alice", Role: "ADMIN"}, {ID: "2", Name: "bob", Role: "USER"}}
+	registerResolvers := func(agh *actograph.Actograph) error {
+		for coordinate, resolver := range map[string]interface{}{
+			"Query.user": func(ctx context.Context, args struct{ ID string }) (*testUser, error) {
+				for _, user := range users {
+					if user.ID == args.ID {
+						return user, nil
+					}
+				}
+				return nil, nil
+			},
+			"Query.users": func(args testUsersArgs) []*testUser {
+				var found []*testUser
+				for _, user := range users {
+					if !strings.HasPrefix(user.Name, args.Filter.Prefix) {
+						continue
+					}
+					if len(args.Filter.Roles) > 0 && args.Filter.Roles[0] != user.Role {
+						continue
+					}
+					found = append(found, user)
+				}
+				return found
+			},
+			"Query.greeting": actograph.ResolverFunc(func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				return "hello " + args["name"].(string), nil
+			}),
+			"Query.broken": func() (string, error) {
+				return "", fmt.Errorf("broken")
+			},
+			"User.id":   func(user *testUser) string { return user.ID },
+			"User.name": func(ctx context.Context, user *testUser) string { return user.Name },
+			"User.role": func(user *testUser, args map[string]interface{}) string { return user.Role },
+		} {
+			if err := agh.RegisterResolver(coordinate, resolver); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	gscm, err := getGQLSchemaWith(registerResolvers, testResolversSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{
+			user(id: "2") { id name role }
+			users { name }
+			admins: users(filter: {prefix: "", roles: [ADMIN]}) { id }
+			greeting(name: "world")
+			broken
+		}`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"admins":[{"id":"1"}],"broken":"fallback (broken)","greeting":"HELLO WORLD","user":{"id":"2","name":"bob","role":"USER"},"users":[{"name":"user-alice"}]}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	_, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		if err := registerResolvers(agh); err != nil {
+			return err
+		}
+		if err := agh.RegisterResolver("Query.missing", func() string { return "" }); err != nil {
+			return err
+		}
+		return agh.RegisterResolver("Post.title", func() string { return "" })
+	}, testResolversSchema)
+	for _, expected := range []string{
+		"resolver registered for 'Query.missing', but type 'Query' has no field 'missing'",
+		"resolver registered for 'Post.title', but type 'Post' is not defined",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("error = %v, expected to contain %q", err, expected)
+		}
+	}
+
+	// fields without resolvers take values from fields of returned structs, methods are not called
+	gscm, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.RegisterResolver("Query.user", func(args struct{ ID string }) *testUserView {
+			return &testUserView{UserID: args.ID, Name: "carol", Kind: "USER"}
+		})
+	}, testResolversSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ user(id: "3") { id name role } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ = json.Marshal(result.Data)
+	expected = `{"user":{"id":"3","name":"carol","role":"USER"}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	agh := actograph.NewActograph()
+	for _, tt := range []struct {
+		coordinate string
+		resolver   interface{}
+		expected   string
+	}{
+		{"Query", func() string { return "" }, "invalid field coordinate 'Query'"},
+		{"Query.user", "user", "expected function"},
+		{"Query.user", func() {}, "should return value or value and error"},
+		{"Query.user", func(a, b, c string) string { return "" }, "takes too many parameters"},
+	} {
+		if err := agh.RegisterResolver(tt.coordinate, tt.resolver); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Fatalf("error = %v, expected to contain %q", err, tt.expected)
+		}
+	}
+}
+
+// testUserView is resolved by fields named or tagged like schema fields
+type testUserView struct {
+	UserID string `json:"id"`
+	Name   string
+	Kind   string `graphql:"role"`
+}
+
+// Role has name of field, but it's not called as resolver
+func (u *testUserView) Role() string {
+	panic("method of source should not be called")
+}
+
+type testQueryResolver struct {
+	users []*testUser
+}
+
+func (r *testQueryResolver) User(ctx context.Context, args struct{ ID string }) (*testUser, error) {
+	for _, user := range r.users {
+		if user.ID == args.ID {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *testQueryResolver) Users(args testUsersArgs) []*testUser {
+	var found []*testUser
+	for _, user := range r.users {
+		if strings.HasPrefix(user.Name, args.Filter.Prefix) {
+			found = append(found, user)
+		}
+	}
+	return found
+}
+
+func (r *testQueryResolver) Greeting(args map[string]interface{}) string {
+	return "hello " + args["name"].(string)
+}
+
+type testUserResolver struct{}
+
+func (testUserResolver) ID(user *testUser) string {
+	return user.ID
+}
+
+func (testUserResolver) Name(ctx context.Context, user *testUser) (string, error) {
+	return user.Name, nil
+}
+
+func (testUserResolver) Role(user *testUser) string {
+	return user.Role
+}
+
+type testInvalidUserResolver struct {
+	testUserResolver
+}
+
+func (testInvalidUserResolver) Role(user *testUser, args struct{ Upper bool }) string {
+	return user.Role
+}
+
+func TestBindType(t *testing.T) {
+	query := &testQueryResolver{users: []*testUser{{ID: "1", Name: "user-alice", Role: "ADMIN"}, {ID: "2", Name: "bob", Role: "USER"}}}
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		if err := agh.BindType("Query", query); err != nil {
+			return err
+		}
+		if err := agh.RegisterResolver("Query.broken", func() (string, error) { return "", fmt.Errorf("broken") }); err != nil {
+			return err
+		}
+		return agh.BindType("User", testUserResolver{})
+	}, testResolversSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{
+		RequestString: `{ user(id: "1") { id name role } users { id } greeting(name: "world") broken version }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	// version has no method, it's resolved by directive
+	expected := `{"broken":"fallback (broken)","greeting":"HELLO WORLD","user":{"id":"1","name":"user-alice","role":"ADMIN"},` +
+		`"users":[{"id":"1"}],"version":"1"}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+
+	_, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		if err := agh.BindType("Query", query); err != nil {
+			return err
+		}
+		if err := agh.BindType("Post", query); err != nil {
+			return err
+		}
+		return agh.BindType("User", testInvalidUserResolver{})
+	}, testResolversSchema)
+	for _, expected := range []string{
+		"type 'Post' is bound, but not defined",
+		"method of actograph_test.testInvalidUserResolver resolving 'User.role': field Upper of struct { Upper bool } doesn't match any argument of field 'role'",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("error = %v, expected to contain %q", err, expected)
+		}
+	}
+
+	// fields without methods, registered resolvers and directives fail the build
+	_, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.BindType("Query", struct{}{})
+	}, testResolversSchema)
+	if expected := "struct {} has no method resolving field 'Query.user'"; err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("error = %v, expected to contain %q", err, expected)
+	}
+	if strings.Contains(err.Error(), "Query.version") {
+		t.Fatalf("field resolved by directive should not be reported: %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.Use(
+			func(next actograph.Handler) actograph.Handler {
+				return func(ctx context.Context, request actograph.RequestQuery) *actograph.Result {
+					calls = append(calls, "outer")
+					// schema directives see root object set by middleware
+					request.RootObject = map[string]interface{}{"key_in_root_obj": "from middleware"}
+					result := next(ctx, request)
+					result.Extensions = map[string]interface{}{"calls": len(calls)}
+					return result
+				}
+			},
+			func(next actograph.Handler) actograph.Handler {
+				return func(ctx context.Context, request actograph.RequestQuery) *actograph.Result {
+					calls = append(calls, "inner")
+					if request.OperationName == "Denied" {
+						return &actograph.Result{Errors: gqlerrors.FormatErrors(fmt.Errorf("denied"))}
+					}
+					return next(ctx, request)
+				}
+			},
+		)
+	}, testContextSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, err := gscm.Do(actograph.RequestQuery{RequestString: `{ test_root }`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := json.Marshal(result)
+	expected := `{"data":{"test_root":"from middleware"},"extensions":{"calls":2}}`
+	if string(data) != expected {
+		t.Fatalf("result = %s, expected %s", data, expected)
+	}
+
+	result, _ = gscm.Do(actograph.RequestQuery{RequestString: `query Denied { test_root }`, OperationName: "Denied"})
+	data, _ = json.Marshal(result)
+	expected = `{"data":null,"errors":[{"message":"denied","locations":[]}],"extensions":{"calls":4}}`
+	if string(data) != expected {
+		t.Fatalf("result = %s, expected %s", data, expected)
+	}
+	if strings.Join(calls, ",") != "outer,inner,outer,inner" {
+		t.Fatalf("calls = %v", calls)
+	}
+
+	if err := gscm.Use(func(next actograph.Handler) actograph.Handler { return next }); err == nil {
+		t.Fatalf("expected error when using middleware after build")
+	}
+}
+
+func TestFieldMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var resolved []string
+	gscm, err := getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.UseField(func(next actograph.FieldResolver) actograph.FieldResolver {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				mu.Lock()
+				resolved = append(resolved, p.Info.FieldName)
+				mu.Unlock()
+				value, err := next(p)
+				if p.Info.FieldName == "double" {
+					return value.(string) + "!", err
+				}
+				return value, err
+			}
+		})
+	}, testDefaultValuesSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+
+	result, _ := gscm.Do(actograph.RequestQuery{RequestString: `{ filter { tags page { size } } double }`})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ := json.Marshal(result.Data)
+	expected := `{"double":"abab!abab!","filter":{"page":{"size":10},"tags":["new"]}}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+	// fields of FilterValue and PageValue have no directives and skip middleware
+	sort.Strings(resolved)
+	if strings.Join(resolved, ",") != "double,filter" {
+		t.Fatalf("resolved = %v, expected only fields with directives", resolved)
+	}
+
+	// enum fields are trivial, directives inherited from schema and directives of client make field non-trivial
+	resolved = nil
+	gscm, err = getGQLSchemaWith(func(agh *actograph.Actograph) error {
+		return agh.UseField(func(next actograph.FieldResolver) actograph.FieldResolver {
+			return func(p graphql.ResolveParams) (interface{}, error) {
+				mu.Lock()
+				resolved = append(resolved, p.Info.FieldName)
+				mu.Unlock()
+				return next(p)
+			}
+		})
+	}, testFieldMiddlewareSchema)
+	if err != nil {
+		t.Fatalf("error when creating schema: %v", err)
+	}
+	result, _ = gscm.Do(actograph.RequestQuery{
+		RequestString: `{ status name upper client: name @upper }`,
+		RootObject:    map[string]interface{}{"status": "ACTIVE", "name": "alice", "upper": "bob"},
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	data, _ = json.Marshal(result.Data)
+	expected = `{"client":"SCHEMA:ALICE","name":"schema:alice","status":"ACTIVE","upper":"SCHEMA:BOB"}`
+	if string(data) != expected {
+		t.Fatalf("data = %s, expected %s", data, expected)
+	}
+	sort.Strings(resolved)
+	if strings.Join(resolved, ",") != "name,name,upper" {
+		t.Fatalf("resolved = %v, expected fields with inherited and client directives", resolved)
+	}
+}
+
+func getGQLSchema(filenames ...string) (*actograph.Actograph, error) {
+	return getGQLSchemaWith(nil, filenames...)
+}
+
+// getGQLSchemaWith calls setup before validating schema
+func getGQLSchemaWith(setup func(agh *actograph.Actograph) error, filenames ...string) (*actograph.Actograph, error) {
+	allFiles := append([]string{exampleDirectives}, filenames...)
+
+	agh, err := actograph.NewActographFiles(allFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("when parse file: %w", err)
+	}
+
+	// RegisterDirectives before parse
+	if err := agh.RegisterDirectives(
+		// todo: move default directives elsewhere
+		directive.NewDirectiveDefinition("deprecated", directive.NewDeprecated),
+		directive.NewDirectiveDefinition("_value", directive.NewValue),
+
+		directive.NewDirectiveDefinition("resolveString", directives.NewDirectiveResolveString),
+		directive.NewDirectiveDefinition("resolveArg", directives.NewDirectiveResolveArg),
+		directive.NewDirectiveDefinition("setContext", directives.NewDirectiveSetContext),
+		directive.NewDirectiveDefinition("getContext", directives.NewDirectiveGetContext),
+		directive.NewDirectiveDefinition("expect", directives.NewDirectiveExpect),
+		directive.NewDirectiveDefinition("requestedFields", directives.NewDirectiveRequestedFields),
+		directive.NewDirectiveDefinition("upper", directives.NewDirectiveUpper),
+		directive.NewDirectiveDefinition("fallback", directives.NewDirectiveFallback),
+		directive.NewDirectiveDefinition("prefix", directives.NewDirectivePrefix),
+		directive.NewDirectiveDefinition("visibleIf", directives.NewDirectiveVisibleIf),
+		directive.NewDirectiveDefinition("numbers", directives.NewDirectiveNumbers),
+	); err != nil {
+		return nil, fmt.Errorf("when registering directives: %w", err)
+	}
+
+	agh.RegisterScalar(scalars.DoubleStringScalarConfig)
+
+	if setup != nil {
+		if err := setup(agh); err != nil {
+			return nil, fmt.Errorf("when setting up schema: %w", err)
+		}
+	}
 
 	if err := agh.Validate(); err != nil {
 		return nil, fmt.Errorf("when validating schema: %w", err)
@@ -0,0 +1,105 @@
+package actograph
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// BindType binds value (usually pointer to service struct) to object type, its exported methods named like fields
+// (case-insensitive) resolve these fields. Methods take the same parameters as functions of RegisterResolver:
+//
+//	func (s *UserResolver) Posts(ctx context.Context, user *User, args PostsArgs) ([]*Post, error)
+//
+// Fields without methods should be resolved by resolver registered by RegisterResolver or by directives of field
+// (including @node and @connection). Schema is not built when type is not defined, when some field has neither
+// method, registered resolver nor directives, when field has both method and registered resolver or when method
+// signature doesn't match field
+func (agh *Actograph) BindType(typeName string, binding interface{}) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	if binding == nil {
+		return fmt.Errorf("binding of type '%s' is nil", typeName)
+	}
+	if _, has := agh.typeBindings[typeName]; has {
+		return fmt.Errorf("type '%s' already bound", typeName)
+	}
+	agh.typeBindings[typeName] = binding
+	return nil
+}
+
+// makeBoundResolvers makes resolvers of fields of bound types from methods of bindings
+func (agh *Actograph) makeBoundResolvers() []error {
+	typeNames := make([]string, 0, len(agh.typeBindings))
+	for typeName := range agh.typeBindings {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	var errs []error
+	for _, typeName := range typeNames {
+		objDefinition, has := agh.objectDefinitions[typeName]
+		if !has {
+			errs = append(errs, fmt.Errorf("type '%s' is bound, but not defined", typeName))
+			continue
+		}
+		binding := reflect.ValueOf(agh.typeBindings[typeName])
+		for _, fieldDefinition := range agh.objectFields(typeName, objDefinition) {
+			coordinate := typeName + "." + fieldDefinition.Name.Value
+			method, has := methodByFieldName(binding, fieldDefinition.Name.Value)
+			_, hasResolver := agh.resolvers[coordinate]
+			switch {
+			case has && hasResolver:
+				errs = append(errs, fmt.Errorf("field '%s' is resolved by both registered resolver and method of %s",
+					coordinate, binding.Type()))
+				continue
+			case hasResolver:
+				continue
+			case !has:
+				if !agh.hasFieldDirectives(fieldDefinition) {
+					errs = append(errs, fmt.Errorf("%s has no method resolving field '%s'", binding.Type(), coordinate))
+				}
+				continue
+			}
+
+			resolve, err := adaptResolver(method, fieldDefinition)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("method of %s resolving '%s': %w", binding.Type(), coordinate, err))
+				continue
+			}
+			agh.fieldResolvers[fieldDefinition] = &fieldResolver{resolve: resolve}
+		}
+	}
+	return errs
+}
+
+// methodByFieldName finds exported method named like field ignoring case
+func methodByFieldName(binding reflect.Value, fieldName string) (reflect.Value, bool) {
+	bindingType := binding.Type()
+	for i := 0; i < bindingType.NumMethod(); i++ {
+		if strings.EqualFold(bindingType.Method(i).Name, fieldName) {
+			return binding.Method(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// hasFieldDirectives reports is field resolved by directives written on it, @node or @connection
+func (agh *Actograph) hasFieldDirectives(fieldDefinition *ast.FieldDefinition) bool {
+	if agh.connectionFields[fieldDefinition] {
+		return true
+	}
+	if _, has := agh.nodeResolvers[fieldDefinition]; has {
+		return true
+	}
+	for _, dir := range fieldDefinition.Directives {
+		if !isHardcodedDirective(dir.Name.Value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,529 @@
+package actograph
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/lexer"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// DefinitionBuilder builds definition of schema in Go code, see Actograph.Add. Builders make the same ast
+// definitions as parsed schema, so built and parsed definitions can extend each other and use the same directives
+type DefinitionBuilder interface {
+	build() (node ast.Node, extensionKind string, err error)
+}
+
+// Add adds definitions made by builders (NewObject, NewInput, NewEnum and their Extend* variants)
+// to definitions parsed from schema. When some builder fails, none of definitions is added.
+// Builders are kept and added again when Reloader rebuilds schema
+func (agh *Actograph) Add(builders ...DefinitionBuilder) (err error) {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	defer func() {
+		// adding of already defined definition panics like parsing
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("when adding definition: %v", recovered)
+		}
+	}()
+
+	// build all definitions first, so failed Add doesn't leave part of them added
+	nodes := make([]ast.Node, len(builders))
+	extensionKinds := make([]string, len(builders))
+	defined := map[string]bool{}
+	for i, builder := range builders {
+		node, extensionKind, err := builder.build()
+		if err != nil {
+			return err
+		}
+		if extensionKind == "" {
+			if err := agh.checkNotDefined(node, defined); err != nil {
+				return err
+			}
+		}
+		nodes[i], extensionKinds[i] = node, extensionKind
+	}
+
+	for i, node := range nodes {
+		switch extensionKinds[i] {
+		case "":
+			agh.addDefinition(node)
+		case lexer.TYPE:
+			agh.addExtensionDefinition(ast.NewTypeExtensionDefinition(&ast.TypeExtensionDefinition{
+				Definition: node.(*ast.ObjectDefinition),
+			}))
+		default:
+			agh.addTypeExtension(markedExtension{kind: extensionKinds[i]}, node)
+		}
+	}
+	agh.builders = append(agh.builders, builders...)
+	return nil
+}
+
+// checkNotDefined returns error when built definition is already defined in actograph or by previous builder of Add
+func (agh *Actograph) checkNotDefined(node ast.Node, defined map[string]bool) error {
+	var kind, name string
+	var has bool
+	switch node := node.(type) {
+	case *ast.ObjectDefinition:
+		kind, name = "object", node.Name.Value
+		_, has = agh.objectDefinitions[name]
+	case *ast.InputObjectDefinition:
+		kind, name = "input object", node.Name.Value
+		_, has = agh.inputObjectDefinitions[name]
+	case *ast.EnumDefinition:
+		kind, name = "enum", node.Name.Value
+		_, has = agh.enumDefinitions[name]
+	default:
+		return nil
+	}
+	if has || defined[kind+" "+name] {
+		return fmt.Errorf("when adding definition: %s with name '%s' already defined", kind, name)
+	}
+	defined[kind+" "+name] = true
+	return nil
+}
+
+// EnumLiteral is enum value in arguments of directives and default values of builders
+type EnumLiteral string
+
+// directiveUsages are directives applied to built definition
+type directiveUsages struct {
+	usages []*ast.Directive
+	err    error
+}
+
+func (d *directiveUsages) add(name string, args map[string]interface{}) {
+	names := make([]string, 0, len(args))
+	for argName := range args {
+		names = append(names, argName)
+	}
+	sort.Strings(names)
+
+	arguments := make([]*ast.Argument, 0, len(args))
+	for _, argName := range names {
+		value, err := astValue(args[argName])
+		if err != nil && d.err == nil {
+			d.err = fmt.Errorf("argument %s of @%s: %w", argName, name, err)
+		}
+		arguments = append(arguments, ast.NewArgument(&ast.Argument{Name: astName(argName), Value: value}))
+	}
+	d.usages = append(d.usages, ast.NewDirective(&ast.Directive{Name: astName(name), Arguments: arguments}))
+}
+
+// ObjectBuilder builds object type or its extension
+type ObjectBuilder struct {
+	definition *ast.ObjectDefinition
+	extension  bool
+	fields     []*FieldBuilder
+	directives directiveUsages
+}
+
+// NewObject starts building object type
+func NewObject(name string) *ObjectBuilder {
+	return &ObjectBuilder{definition: ast.NewObjectDefinition(&ast.ObjectDefinition{Name: astName(name)})}
+}
+
+// ExtendObject starts building extension of object type, like `extend type Name`
+func ExtendObject(name string) *ObjectBuilder {
+	builder := NewObject(name)
+	builder.extension = true
+	return builder
+}
+
+// Description sets description of object type
+func (b *ObjectBuilder) Description(description string) *ObjectBuilder {
+	b.definition.Description = astDescription(description)
+	return b
+}
+
+// Implements adds interfaces implemented by object type
+func (b *ObjectBuilder) Implements(interfaces ...string) *ObjectBuilder {
+	for _, name := range interfaces {
+		b.definition.Interfaces = append(b.definition.Interfaces, named(name))
+	}
+	return b
+}
+
+// Directive applies directive with arguments, see EnumLiteral for enum arguments
+func (b *ObjectBuilder) Directive(name string, args map[string]interface{}) *ObjectBuilder {
+	b.directives.add(name, args)
+	return b
+}
+
+// Field adds fields of object type
+func (b *ObjectBuilder) Field(fields ...*FieldBuilder) *ObjectBuilder {
+	b.fields = append(b.fields, fields...)
+	return b
+}
+
+func (b *ObjectBuilder) build() (ast.Node, string, error) {
+	definition := *b.definition
+	if b.directives.err != nil {
+		return nil, "", fmt.Errorf("type %s: %w", definition.Name.Value, b.directives.err)
+	}
+	definition.Directives = b.directives.usages
+	definition.Fields = make([]*ast.FieldDefinition, len(b.fields))
+	for i, field := range b.fields {
+		fieldDefinition, err := field.build()
+		if err != nil {
+			return nil, "", fmt.Errorf("type %s: %w", definition.Name.Value, err)
+		}
+		definition.Fields[i] = fieldDefinition
+	}
+
+	if b.extension {
+		return &definition, lexer.TYPE, nil
+	}
+	return &definition, "", nil
+}
+
+// FieldBuilder builds field of object type
+type FieldBuilder struct {
+	definition *ast.FieldDefinition
+	typeName   string
+	arguments  []*InputValueBuilder
+	directives directiveUsages
+}
+
+// NewField starts building field, type is written like in schema: "[User!]!"
+func NewField(name string, typeName string) *FieldBuilder {
+	return &FieldBuilder{
+		definition: ast.NewFieldDefinition(&ast.FieldDefinition{Name: astName(name)}),
+		typeName:   typeName,
+	}
+}
+
+// Description sets description of field
+func (b *FieldBuilder) Description(description string) *FieldBuilder {
+	b.definition.Description = astDescription(description)
+	return b
+}
+
+// Argument adds arguments of field
+func (b *FieldBuilder) Argument(arguments ...*InputValueBuilder) *FieldBuilder {
+	b.arguments = append(b.arguments, arguments...)
+	return b
+}
+
+// Directive applies directive with arguments, see EnumLiteral for enum arguments
+func (b *FieldBuilder) Directive(name string, args map[string]interface{}) *FieldBuilder {
+	b.directives.add(name, args)
+	return b
+}
+
+func (b *FieldBuilder) build() (*ast.FieldDefinition, error) {
+	definition := *b.definition
+	name := definition.Name.Value
+	if b.directives.err != nil {
+		return nil, fmt.Errorf("field %s: %w", name, b.directives.err)
+	}
+	fieldType, err := parseTypeReference(b.typeName)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", name, err)
+	}
+	definition.Type = fieldType
+	definition.Directives = b.directives.usages
+	definition.Arguments = make([]*ast.InputValueDefinition, len(b.arguments))
+	for i, argument := range b.arguments {
+		argDefinition, err := argument.build()
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		definition.Arguments[i] = argDefinition
+	}
+	return &definition, nil
+}
+
+// InputValueBuilder builds argument of field or field of input object
+type InputValueBuilder struct {
+	definition   *ast.InputValueDefinition
+	typeName     string
+	defaultValue interface{}
+	hasDefault   bool
+	directives   directiveUsages
+}
+
+// NewInputValue starts building argument or input field, type is written like in schema: "[String!]"
+func NewInputValue(name string, typeName string) *InputValueBuilder {
+	return &InputValueBuilder{
+		definition: ast.NewInputValueDefinition(&ast.InputValueDefinition{Name: astName(name)}),
+		typeName:   typeName,
+	}
+}
+
+// Description sets description of argument or input field
+func (b *InputValueBuilder) Description(description string) *InputValueBuilder {
+	b.definition.Description = astDescription(description)
+	return b
+}
+
+// Default sets default value, see EnumLiteral for enum values
+func (b *InputValueBuilder) Default(value interface{}) *InputValueBuilder {
+	b.defaultValue, b.hasDefault = value, true
+	return b
+}
+
+// Directive applies directive with arguments, see EnumLiteral for enum arguments
+func (b *InputValueBuilder) Directive(name string, args map[string]interface{}) *InputValueBuilder {
+	b.directives.add(name, args)
+	return b
+}
+
+func (b *InputValueBuilder) build() (*ast.InputValueDefinition, error) {
+	definition := *b.definition
+	name := definition.Name.Value
+	if b.directives.err != nil {
+		return nil, fmt.Errorf("%s: %w", name, b.directives.err)
+	}
+	valueType, err := parseTypeReference(b.typeName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	definition.Type = valueType
+	definition.Directives = b.directives.usages
+	if b.hasDefault {
+		definition.DefaultValue, err = astValue(b.defaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("default value of %s: %w", name, err)
+		}
+	}
+	return &definition, nil
+}
+
+// InputBuilder builds input object type or its extension
+type InputBuilder struct {
+	definition *ast.InputObjectDefinition
+	extension  bool
+	fields     []*InputValueBuilder
+	directives directiveUsages
+}
+
+// NewInput starts building input object type
+func NewInput(name string) *InputBuilder {
+	return &InputBuilder{definition: ast.NewInputObjectDefinition(&ast.InputObjectDefinition{Name: astName(name)})}
+}
+
+// ExtendInput starts building extension of input object type, like `extend input Name`
+func ExtendInput(name string) *InputBuilder {
+	builder := NewInput(name)
+	builder.extension = true
+	return builder
+}
+
+// Description sets description of input object type
+func (b *InputBuilder) Description(description string) *InputBuilder {
+	b.definition.Description = astDescription(description)
+	return b
+}
+
+// Directive applies directive with arguments, see EnumLiteral for enum arguments
+func (b *InputBuilder) Directive(name string, args map[string]interface{}) *InputBuilder {
+	b.directives.add(name, args)
+	return b
+}
+
+// Field adds fields of input object type
+func (b *InputBuilder) Field(fields ...*InputValueBuilder) *InputBuilder {
+	b.fields = append(b.fields, fields...)
+	return b
+}
+
+func (b *InputBuilder) build() (ast.Node, string, error) {
+	definition := *b.definition
+	name := definition.Name.Value
+	if b.directives.err != nil {
+		return nil, "", fmt.Errorf("input %s: %w", name, b.directives.err)
+	}
+	definition.Directives = b.directives.usages
+	definition.Fields = make([]*ast.InputValueDefinition, len(b.fields))
+	for i, field := range b.fields {
+		fieldDefinition, err := field.build()
+		if err != nil {
+			return nil, "", fmt.Errorf("input %s: field %w", name, err)
+		}
+		definition.Fields[i] = fieldDefinition
+	}
+
+	if b.extension {
+		return &definition, lexer.INPUT, nil
+	}
+	return &definition, "", nil
+}
+
+// EnumBuilder builds enum type or its extension
+type EnumBuilder struct {
+	definition *ast.EnumDefinition
+	extension  bool
+	values     []*EnumValueBuilder
+	directives directiveUsages
+}
+
+// NewEnum starts building enum type
+func NewEnum(name string) *EnumBuilder {
+	return &EnumBuilder{definition: ast.NewEnumDefinition(&ast.EnumDefinition{Name: astName(name)})}
+}
+
+// ExtendEnum starts building extension of enum type, like `extend enum Name`
+func ExtendEnum(name string) *EnumBuilder {
+	builder := NewEnum(name)
+	builder.extension = true
+	return builder
+}
+
+// Description sets description of enum type
+func (b *EnumBuilder) Description(description string) *EnumBuilder {
+	b.definition.Description = astDescription(description)
+	return b
+}
+
+// Directive applies directive with arguments, see EnumLiteral for enum arguments
+func (b *EnumBuilder) Directive(name string, args map[string]interface{}) *EnumBuilder {
+	b.directives.add(name, args)
+	return b
+}
+
+// Values adds values without description and directives
+func (b *EnumBuilder) Values(names ...string) *EnumBuilder {
+	for _, name := range names {
+		b.values = append(b.values, NewEnumValue(name))
+	}
+	return b
+}
+
+// Value adds values built by NewEnumValue
+func (b *EnumBuilder) Value(values ...*EnumValueBuilder) *EnumBuilder {
+	b.values = append(b.values, values...)
+	return b
+}
+
+func (b *EnumBuilder) build() (ast.Node, string, error) {
+	definition := *b.definition
+	name := definition.Name.Value
+	if b.directives.err != nil {
+		return nil, "", fmt.Errorf("enum %s: %w", name, b.directives.err)
+	}
+	definition.Directives = b.directives.usages
+	definition.Values = make([]*ast.EnumValueDefinition, len(b.values))
+	for i, value := range b.values {
+		valueDefinition := *value.definition
+		if value.directives.err != nil {
+			return nil, "", fmt.Errorf("enum %s: value %s: %w", name, valueDefinition.Name.Value, value.directives.err)
+		}
+		valueDefinition.Directives = value.directives.usages
+		definition.Values[i] = &valueDefinition
+	}
+
+	if b.extension {
+		return &definition, lexer.ENUM, nil
+	}
+	return &definition, "", nil
+}
+
+// EnumValueBuilder builds value of enum type
+type EnumValueBuilder struct {
+	definition *ast.EnumValueDefinition
+	directives directiveUsages
+}
+
+// NewEnumValue starts building value of enum type
+func NewEnumValue(name string) *EnumValueBuilder {
+	return &EnumValueBuilder{definition: ast.NewEnumValueDefinition(&ast.EnumValueDefinition{Name: astName(name)})}
+}
+
+// Description sets description of enum value
+func (b *EnumValueBuilder) Description(description string) *EnumValueBuilder {
+	b.definition.Description = astDescription(description)
+	return b
+}
+
+// Directive applies directive with arguments, see EnumLiteral for enum arguments
+func (b *EnumValueBuilder) Directive(name string, args map[string]interface{}) *EnumValueBuilder {
+	b.directives.add(name, args)
+	return b
+}
+
+func astName(name string) *ast.Name {
+	return ast.NewName(&ast.Name{Value: name})
+}
+
+func astDescription(description string) *ast.StringValue {
+	return ast.NewStringValue(&ast.StringValue{Value: description})
+}
+
+// parseTypeReference parses type written like in schema: "[User!]!"
+func parseTypeReference(typeName string) (ast.Type, error) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source:  &source.Source{Body: []byte("input T { f: " + typeName + " }")},
+		Options: parser.ParseOptions{NoLocation: true},
+	})
+	if err != nil || len(doc.Definitions) != 1 {
+		return nil, fmt.Errorf("invalid type %q", typeName)
+	}
+	input, ok := doc.Definitions[0].(*ast.InputObjectDefinition)
+	if !ok || len(input.Fields) != 1 || input.Fields[0].DefaultValue != nil || len(input.Fields[0].Directives) > 0 {
+		return nil, fmt.Errorf("invalid type %q", typeName)
+	}
+	return input.Fields[0].Type, nil
+}
+
+// astValue converts Go value to literal: strings, numbers, booleans, EnumLiteral, slices and maps
+// with string keys are supported
+func astValue(value interface{}) (ast.Value, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("null literals are not supported")
+	case ast.Value:
+		return value, nil
+	case EnumLiteral:
+		return ast.NewEnumValue(&ast.EnumValue{Value: string(value)}), nil
+	case string:
+		return ast.NewStringValue(&ast.StringValue{Value: value}), nil
+	case bool:
+		return ast.NewBooleanValue(&ast.BooleanValue{Value: value}), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ast.NewIntValue(&ast.IntValue{Value: strconv.FormatInt(rv.Int(), 10)}), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ast.NewIntValue(&ast.IntValue{Value: strconv.FormatUint(rv.Uint(), 10)}), nil
+	case reflect.Float32, reflect.Float64:
+		return ast.NewFloatValue(&ast.FloatValue{Value: strconv.FormatFloat(rv.Float(), 'g', -1, 64)}), nil
+	case reflect.Slice, reflect.Array:
+		values := make([]ast.Value, rv.Len())
+		for i := range values {
+			item, err := astValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[i] = item
+		}
+		return ast.NewListValue(&ast.ListValue{Values: values}), nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map keys should be strings, got %s", rv.Type().Key())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		fields := make([]*ast.ObjectField, len(keys))
+		for i, key := range keys {
+			fieldValue, err := astValue(rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface())
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = ast.NewObjectField(&ast.ObjectField{Name: astName(key), Value: fieldValue})
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{Fields: fields}), nil
+	}
+	return nil, fmt.Errorf("unsupported literal value %v of type %T", value, value)
+}
@@ -0,0 +1,207 @@
+package actograph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph/directive"
+)
+
+// connectionDirectiveName is hardcoded directive that turns list field to relay-style cursor connection:
+//
+//	directive @connection on FIELD_DEFINITION
+//
+// field `users: [User!]!` becomes `users(first: Int, after: String, last: Int, before: String): UserConnection!`,
+// types UserConnection, UserEdge and PageInfo are defined automatically when they are not defined in schema
+const connectionDirectiveName = "connection"
+
+const pageInfoTypeName = "PageInfo"
+
+// makeConnections replaces types of fields marked by @connection with connection types
+func (agh *Actograph) makeConnections() error {
+	for objName, objDefinition := range agh.objectDefinitions {
+		for _, fieldDefinition := range agh.objectFields(objName, objDefinition) {
+			if agh.connectionFields[fieldDefinition] || !hasDirective(fieldDefinition.Directives, connectionDirectiveName) {
+				continue
+			}
+			if err := agh.makeConnection(fieldDefinition); err != nil {
+				return fmt.Errorf("when making connection for field %s.%s: %w", objName, fieldDefinition.Name.Value, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (agh *Actograph) makeConnection(fieldDefinition *ast.FieldDefinition) error {
+	fieldType := fieldDefinition.Type
+	nonNull, isNonNull := fieldType.(*ast.NonNull)
+	if isNonNull {
+		fieldType = nonNull.Type
+	}
+	list, isList := fieldType.(*ast.List)
+	if !isList {
+		return fmt.Errorf("@connection can be used only on list fields")
+	}
+
+	nodeName := namedTypeName(list.Type)
+	connectionName := nodeName + "Connection"
+	edgeName := nodeName + "Edge"
+
+	agh.addSyntheticObject(pageInfoTypeName, "Information about pagination in a connection.",
+		syntheticField("hasNextPage", nonNullNamed("Boolean")),
+		syntheticField("hasPreviousPage", nonNullNamed("Boolean")),
+		syntheticField("startCursor", named("String")),
+		syntheticField("endCursor", named("String")),
+	)
+	agh.addSyntheticObject(edgeName, fmt.Sprintf("An edge in a connection of %s.", nodeName),
+		syntheticField("node", list.Type),
+		syntheticField("cursor", nonNullNamed("String")),
+	)
+	agh.addSyntheticObject(connectionName, fmt.Sprintf("A connection to a list of %s.", nodeName),
+		syntheticField("edges", ast.NewNonNull(&ast.NonNull{Type: ast.NewList(&ast.List{Type: nonNullNamed(edgeName)})})),
+		syntheticField("pageInfo", nonNullNamed(pageInfoTypeName)),
+	)
+
+	var connectionType ast.Type = named(connectionName)
+	if isNonNull {
+		connectionType = ast.NewNonNull(&ast.NonNull{Type: connectionType})
+	}
+	fieldDefinition.Type = connectionType
+
+	for _, arg := range []struct{ name, typeName string }{{"first", "Int"}, {"after", "String"}, {"last", "Int"}, {"before", "String"}} {
+		if !hasArgument(fieldDefinition.Arguments, arg.name) {
+			fieldDefinition.Arguments = append(fieldDefinition.Arguments, ast.NewInputValueDefinition(&ast.InputValueDefinition{
+				Name: ast.NewName(&ast.Name{Value: arg.name}),
+				Type: named(arg.typeName),
+			}))
+		}
+	}
+
+	agh.connectionFields[fieldDefinition] = true
+	return nil
+}
+
+// addSyntheticObject defines object type unless object with the same name is defined in schema
+func (agh *Actograph) addSyntheticObject(name string, description string, fields ...*ast.FieldDefinition) {
+	if _, has := agh.objectDefinitions[name]; has {
+		return
+	}
+	agh.objectDefinitions[name] = ast.NewObjectDefinition(&ast.ObjectDefinition{
+		Name:        ast.NewName(&ast.Name{Value: name}),
+		Description: ast.NewStringValue(&ast.StringValue{Value: description}),
+		Fields:      fields,
+	})
+}
+
+func syntheticField(name string, t ast.Type) *ast.FieldDefinition {
+	return ast.NewFieldDefinition(&ast.FieldDefinition{
+		Name: ast.NewName(&ast.Name{Value: name}),
+		Type: t,
+	})
+}
+
+func named(name string) *ast.Named {
+	return ast.NewNamed(&ast.Named{Name: ast.NewName(&ast.Name{Value: name})})
+}
+
+func nonNullNamed(name string) *ast.NonNull {
+	return ast.NewNonNull(&ast.NonNull{Type: named(name)})
+}
+
+func hasDirective(directives []*ast.Directive, name string) bool {
+	for _, dir := range directives {
+		if dir.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasArgument(args []*ast.InputValueDefinition, name string) bool {
+	for _, arg := range args {
+		if arg.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// connectionResolver is the last directive of @connection field, it makes connection from resolved list
+// or directive.Page
+type connectionResolver struct{}
+
+func (c *connectionResolver) Phase() directive.Phase {
+	return directive.PhaseAfterResolve
+}
+
+func (c *connectionResolver) Priority() int {
+	return math.MinInt
+}
+
+func (c *connectionResolver) Execute(
+	ctx context.Context,
+	_ interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	var page directive.Page
+	switch value := resolvedValue.(type) {
+	case nil:
+		return nil, ctx, nil
+	case directive.Page:
+		page = value
+	case *directive.Page:
+		page = *value
+	default:
+		rv := reflect.ValueOf(resolvedValue)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, ctx, fmt.Errorf("@connection expects list or directive.Page as resolved value, got %T", resolvedValue)
+		}
+		nodes := make([]interface{}, rv.Len())
+		for i := range nodes {
+			nodes[i] = rv.Index(i).Interface()
+		}
+		var err error
+		page, err = directive.SlicePage(nodes, directive.ConnectionArgsFrom(fieldArgs))
+		if err != nil {
+			return nil, ctx, err
+		}
+	}
+
+	edges := make([]interface{}, len(page.Nodes))
+	var startCursor, endCursor interface{}
+	for i, node := range page.Nodes {
+		var cursor string
+		if page.Cursors != nil {
+			cursor = page.Cursors[i]
+		} else {
+			cursor = directive.EncodeCursor(page.Offset + i)
+		}
+		if i == 0 {
+			startCursor = cursor
+		}
+		endCursor = cursor
+		edges[i] = map[string]interface{}{
+			"node":   node,
+			"cursor": cursor,
+		}
+	}
+
+	return map[string]interface{}{
+		"edges": edges,
+		"pageInfo": map[string]interface{}{
+			"hasNextPage":     page.HasNextPage,
+			"hasPreviousPage": page.HasPreviousPage,
+			"startCursor":     startCursor,
+			"endCursor":       endCursor,
+		},
+	}, ctx, nil
+}
+
+func (c *connectionResolver) Define(_ string, _ interface{}) error {
+	return nil
+}
@@ -1,9 +1,14 @@
 package actograph
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
-	"io"
+	"io/fs"
 	"os"
+	"path"
+	"sort"
+	"strings"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
@@ -13,19 +18,24 @@ import (
 
 func NewActograph() *Actograph {
 	return &Actograph{
-		directiveDeclarations: map[string]directive.Definition{},
+		registrations: newRegistrations(),
 
 		directiveDefinitions:   map[string]*ast.DirectiveDefinition{},
 		objectDefinitions:      map[string]*ast.ObjectDefinition{},
 		extensionDefinitions:   map[string][]*ast.TypeExtensionDefinition{},
+		connectionFields:       map[*ast.FieldDefinition]bool{},
+		nodeResolvers:          map[*ast.FieldDefinition]directive.Directive{},
+		fieldResolvers:         map[*ast.FieldDefinition]directive.Directive{},
 		inputObjectDefinitions: map[string]*ast.InputObjectDefinition{},
 		enumDefinitions:        map[string]*ast.EnumDefinition{},
 		unionDefinitions:       map[string]*ast.UnionDefinition{},
+		interfaceDefinitions:   map[string]*ast.InterfaceDefinition{},
 		declaredScalars:        map[string]ScalarDefinition{},
 
 		enums:        map[string]*graphql.Enum{},
 		objects:      map[string]*graphql.Object{},
 		unions:       map[string]*graphql.Union{},
+		interfaces:   map[string]*graphql.Interface{},
 		inputObjects: map[string]*graphql.InputObject{},
 		scalars: map[string]*graphql.Scalar{
 			// check for scalar or return object
@@ -46,24 +56,147 @@ func NewActographBytes(graphqlFile []byte) (*Actograph, error) {
 	return gscm, gscm.Parse(graphqlFile)
 }
 
+// NewActographFiles parses every file as separate source, so locations in errors have file names
 func NewActographFiles(filenames ...string) (*Actograph, error) {
-	files := make([]io.Reader, len(filenames))
-	for i, filename := range filenames {
-		f, err := os.Open(filename)
-		if err != nil {
-			return nil, fmt.Errorf("when reading schema in file %s: %w", filename, err)
+	return newActographSources(func() ([]schemaFile, error) {
+		files := make([]schemaFile, len(filenames))
+		for i, filename := range filenames {
+			body, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, fmt.Errorf("when reading schema in file %s: %w", filename, err)
+			}
+			files[i] = schemaFile{name: filename, body: body}
 		}
-		files[i] = f
-	}
+		return files, nil
+	})
+}
+
+// NewActographFS parses files of fsys (like embed.FS) matching patterns. Patterns have syntax of path.Match,
+// and element "**" matches any number of directories: "schema/**/*.graphql". Files are parsed in order of
+// patterns, files matching the same pattern are sorted by name, every file is parsed once
+func NewActographFS(fsys fs.FS, patterns ...string) (*Actograph, error) {
+	return newActographSources(func() ([]schemaFile, error) {
+		var files []schemaFile
+		read := map[string]bool{}
+		for _, pattern := range patterns {
+			filenames, err := globFS(fsys, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("when matching files by pattern %s: %w", pattern, err)
+			}
+			if len(filenames) == 0 {
+				return nil, fmt.Errorf("no files match pattern %s", pattern)
+			}
+			for _, filename := range filenames {
+				if read[filename] {
+					continue
+				}
+				read[filename] = true
+
+				body, err := fs.ReadFile(fsys, filename)
+				if err != nil {
+					return nil, fmt.Errorf("when reading schema in file %s: %w", filename, err)
+				}
+				files = append(files, schemaFile{name: filename, body: body})
+			}
+		}
+		return files, nil
+	})
+}
+
+// schemaFile is schema source, its name is used in locations of errors
+type schemaFile struct {
+	name string
+	body []byte
+}
 
-	gqlSchemaData, err := io.ReadAll(io.MultiReader(files...))
+// newActographSources parses files returned by readSources, actograph keeps readSources to be reloaded
+// by Reloader
+func newActographSources(readSources func() ([]schemaFile, error)) (*Actograph, error) {
+	files, err := readSources()
 	if err != nil {
-		return nil, fmt.Errorf("when reading files: %w", err)
+		return nil, err
 	}
-	agh, err := NewActographBytes(gqlSchemaData)
-	if err != nil {
-		return nil, fmt.Errorf("when parse file: %w", err)
+	agh := NewActograph()
+	if err := agh.parseFiles(files); err != nil {
+		return nil, err
 	}
-
+	agh.readSources = readSources
+	agh.sourcesFingerprint = fingerprintOf(files)
 	return agh, nil
 }
+
+func (agh *Actograph) parseFiles(files []schemaFile) error {
+	for _, file := range files {
+		if err := agh.parseSource(file.name, file.body); err != nil {
+			return fmt.Errorf("when parse file %s: %w", file.name, err)
+		}
+	}
+	return nil
+}
+
+// fingerprintOf is hash of names and contents of files to find changes
+func fingerprintOf(files []schemaFile) [sha256.Size]byte {
+	hash := sha256.New()
+	for _, file := range files {
+		// lengths separate names and bodies
+		_, _ = fmt.Fprintf(hash, "%d:%s%d:", len(file.name), file.name, len(file.body))
+		hash.Write(file.body)
+	}
+	var fingerprint [sha256.Size]byte
+	hash.Sum(fingerprint[:0])
+	return fingerprint
+}
+
+// globFS returns sorted names of files in fsys matching pattern, element "**" matches zero or more directories
+func globFS(fsys fs.FS, pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	elements := strings.Split(pattern, "/")
+
+	// only directory without wildcards is walked
+	root := "."
+	for i, element := range elements {
+		if i == len(elements)-1 || strings.ContainsAny(element, `*?[\`) {
+			if i > 0 {
+				root = path.Join(elements[:i]...)
+			}
+			break
+		}
+	}
+
+	var filenames []string
+	err := fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && matchElements(elements, strings.Split(name, "/")) {
+			filenames = append(filenames, name)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+func matchElements(pattern []string, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchElements(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	matched, _ := path.Match(pattern[0], name[0])
+	return matched && matchElements(pattern[1:], name[1:])
+}
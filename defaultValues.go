@@ -0,0 +1,67 @@
+package actograph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/printer"
+
+	"github.com/actord/actograph/directive"
+)
+
+// coerceDefaultValue converts default value of argument or input field to go value of its type, the same value
+// directives get when argument is passed in request. Invalid default value is collected to agh.defaultValueErrors
+func (agh *Actograph) coerceDefaultValue(ttype graphql.Type, definition *ast.InputValueDefinition) interface{} {
+	if definition.DefaultValue == nil {
+		return nil
+	}
+	inputType, ok := ttype.(graphql.Input)
+	if !ok {
+		agh.defaultValueErrors = append(agh.defaultValueErrors, fmt.Errorf(
+			"'%s' at %s has default value, but its type %s is not input type",
+			definition.Name.Value, locationString(definition.Loc), ttype,
+		))
+		return nil
+	}
+	if messages := agh.literalErrors(inputType, definition.DefaultValue); len(messages) > 0 {
+		agh.defaultValueErrors = append(agh.defaultValueErrors, fmt.Errorf(
+			"'%s' at %s has invalid default value %s: %s",
+			definition.Name.Value, locationString(definition.Loc), printer.Print(definition.DefaultValue),
+			strings.Join(messages, " "),
+		))
+		return nil
+	}
+	return directive.CoerceLiteral(definition.DefaultValue, inputType)
+}
+
+// fillInputDefaultValues sets default values of input fields. Default value of input object takes default values
+// of its missing fields, so input objects used by default value are filled first
+func (agh *Actograph) fillInputDefaultValues() {
+	filled := map[string]bool{}
+	var fill func(name string)
+	fill = func(name string) {
+		inputObject, has := agh.inputObjects[name]
+		if filled[name] || !has {
+			return
+		}
+		// marked before filling fields, so recursive input objects take missing defaults as null
+		filled[name] = true
+
+		for _, fieldDefinition := range agh.inputObjectDefinitions[name].Fields {
+			if fieldDefinition.DefaultValue == nil || !agh.isArgumentVisible(fieldDefinition) {
+				continue
+			}
+			fill(namedTypeName(fieldDefinition.Type))
+
+			fieldConfig := agh.makeInputField(fieldDefinition)
+			fieldConfig.DefaultValue = agh.coerceDefaultValue(fieldConfig.Type, fieldDefinition)
+			inputObject.AddFieldConfig(fieldDefinition.Name.Value, fieldConfig)
+		}
+	}
+
+	for name := range agh.inputObjectDefinitions {
+		fill(name)
+	}
+}
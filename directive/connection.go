@@ -0,0 +1,113 @@
+package directive
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const cursorPrefix = "cursor:"
+
+// ConnectionArgs are pagination arguments of @connection field
+type ConnectionArgs struct {
+	First  *int
+	After  string
+	Last   *int
+	Before string
+}
+
+// ConnectionArgsFrom takes pagination arguments from field arguments of @connection field
+func ConnectionArgsFrom(fieldArgs map[string]interface{}) ConnectionArgs {
+	args := ConnectionArgs{}
+	if first, ok := fieldArgs["first"].(int); ok {
+		args.First = &first
+	}
+	if last, ok := fieldArgs["last"].(int); ok {
+		args.Last = &last
+	}
+	args.After, _ = fieldArgs["after"].(string)
+	args.Before, _ = fieldArgs["before"].(string)
+	return args
+}
+
+// Page is a part of connection nodes. Directive of @connection field can resolve Page instead of the whole list
+// to load only requested nodes (see ConnectionArgsFrom), otherwise resolved list is sliced by pagination arguments
+type Page struct {
+	Nodes []interface{}
+	// Cursors of nodes, optional: when nil cursors are made from Offset with EncodeCursor
+	Cursors []string
+	// Offset of the first node in the whole list
+	Offset int
+
+	HasPreviousPage bool
+	HasNextPage     bool
+}
+
+// EncodeCursor makes opaque cursor of node by its offset in the list
+func EncodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeCursor returns offset of node encoded by EncodeCursor
+func DecodeCursor(cursor string) (int, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	return offset, nil
+}
+
+// SlicePage takes page of nodes from the whole list by pagination arguments
+func SlicePage(nodes []interface{}, args ConnectionArgs) (Page, error) {
+	start, end := 0, len(nodes)
+	if args.After != "" {
+		after, err := DecodeCursor(args.After)
+		if err != nil {
+			return Page{}, err
+		}
+		if after+1 > start {
+			start = after + 1
+		}
+	}
+	if args.Before != "" {
+		before, err := DecodeCursor(args.Before)
+		if err != nil {
+			return Page{}, err
+		}
+		if before < end {
+			end = before
+		}
+	}
+	if start > end {
+		start = end
+	}
+
+	if args.First != nil {
+		if *args.First < 0 {
+			return Page{}, fmt.Errorf("argument first should be non-negative, got %d", *args.First)
+		}
+		if start+*args.First < end {
+			end = start + *args.First
+		}
+	}
+	if args.Last != nil {
+		if *args.Last < 0 {
+			return Page{}, fmt.Errorf("argument last should be non-negative, got %d", *args.Last)
+		}
+		if end-*args.Last > start {
+			start = end - *args.Last
+		}
+	}
+
+	return Page{
+		Nodes:           nodes[start:end],
+		Offset:          start,
+		HasPreviousPage: start > 0,
+		HasNextPage:     end < len(nodes),
+	}, nil
+}
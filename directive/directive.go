@@ -10,10 +10,15 @@ import (
 // ErrStopExecutionWithoutError can be returned from directive.Execute and works like "break" in for loops but on directives chain
 var ErrStopExecutionWithoutError = fmt.Errorf("stop execution without error")
 
+// ErrOmitField can be returned from directive.Execute to remove field from response object instead of resolving null.
+// Rest of directives chain (including PhaseOnError directives) is skipped. Works for non-null fields too
+var ErrOmitField = fmt.Errorf("omit field")
+
 type Arguments map[string]ast.Value
 
 type Directive interface {
-	// Execute defined directive (runtime)
+	// Execute defined directive (runtime).
+	// For field directives ctx carries graphql.ResolveInfo of the field, see ResolveInfoFromContext and RequestedFields
 	Execute(
 		ctx context.Context,
 		source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
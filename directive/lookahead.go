@@ -0,0 +1,309 @@
+package directive
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+type resolveInfoContextKey struct{}
+
+// ContextWithResolveInfo returns copy of ctx that carries execution info of currently resolving field.
+// Actograph calls it before directives chain, so directives can take info with ResolveInfoFromContext
+func ContextWithResolveInfo(ctx context.Context, info graphql.ResolveInfo) context.Context {
+	return context.WithValue(ctx, resolveInfoContextKey{}, info)
+}
+
+// ResolveInfoFromContext returns execution info of currently resolving field.
+// ok is false when ctx was not made by ContextWithResolveInfo (for example for directives on schema)
+func ResolveInfoFromContext(ctx context.Context) (info graphql.ResolveInfo, ok bool) {
+	info, ok = ctx.Value(resolveInfoContextKey{}).(graphql.ResolveInfo)
+	return info, ok
+}
+
+// SelectedField is a field requested somewhere below currently resolving field
+type SelectedField struct {
+	Name      string                 // field name as declared in schema
+	Alias     string                 // response key, equals Name when alias is not used
+	Path      []string               // field names from the first child level down to this field (Path[len(Path)-1] == Name)
+	Arguments map[string]interface{} // argument values with substituted variables and default values
+}
+
+// PathString returns Path joined by dot, like "author.name"
+func (f SelectedField) PathString() string {
+	return strings.Join(f.Path, ".")
+}
+
+// RequestedFields is a shortcut for CollectFields with info taken from context.
+// It returns nil when there is no execution info in ctx
+func RequestedFields(ctx context.Context) []SelectedField {
+	info, ok := ResolveInfoFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return CollectFields(info)
+}
+
+// CollectFields returns flattened list of child fields requested under current field in order of appearance.
+// Fragment spreads and inline fragments are expanded, fields excluded by @skip or @include are omitted
+// and the same response key requested several times is listed once. Meta fields like __typename are omitted.
+func CollectFields(info graphql.ResolveInfo) []SelectedField {
+	c := &fieldsCollector{
+		info:    info,
+		visited: map[string]bool{},
+	}
+	parentType := namedType(info.ReturnType)
+	for _, fieldAST := range info.FieldASTs {
+		if fieldAST == nil {
+			continue
+		}
+		c.collect(fieldAST.SelectionSet, parentType, nil, nil)
+	}
+	return c.fields
+}
+
+type fieldsCollector struct {
+	info    graphql.ResolveInfo
+	fields  []SelectedField
+	visited map[string]bool // response key paths
+}
+
+func (c *fieldsCollector) collect(selectionSet *ast.SelectionSet, parentType graphql.Type, path, keyPath []string) {
+	if selectionSet == nil {
+		return
+	}
+
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if !c.shouldInclude(selection.Directives) {
+				continue
+			}
+			name := selection.Name.Value
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			alias := name
+			if selection.Alias != nil {
+				alias = selection.Alias.Value
+			}
+
+			fieldPath := appendPath(path, name)
+			fieldKeyPath := appendPath(keyPath, alias)
+			fieldDefinition := c.fieldDefinition(parentType, name)
+
+			key := strings.Join(fieldKeyPath, ".")
+			if !c.visited[key] {
+				c.visited[key] = true
+				c.fields = append(c.fields, SelectedField{
+					Name:      name,
+					Alias:     alias,
+					Path:      fieldPath,
+					Arguments: c.argumentValues(fieldDefinition, selection.Arguments),
+				})
+			}
+
+			var fieldType graphql.Type
+			if fieldDefinition != nil {
+				fieldType = namedType(fieldDefinition.Type)
+			}
+			c.collect(selection.SelectionSet, fieldType, fieldPath, fieldKeyPath)
+		case *ast.InlineFragment:
+			if !c.shouldInclude(selection.Directives) {
+				continue
+			}
+			c.collect(selection.SelectionSet, c.typeCondition(selection.TypeCondition, parentType), path, keyPath)
+		case *ast.FragmentSpread:
+			if !c.shouldInclude(selection.Directives) {
+				continue
+			}
+			fragment, ok := c.info.Fragments[selection.Name.Value].(*ast.FragmentDefinition)
+			if !ok {
+				continue
+			}
+			c.collect(fragment.SelectionSet, c.typeCondition(fragment.TypeCondition, parentType), path, keyPath)
+		}
+	}
+}
+
+func (c *fieldsCollector) shouldInclude(directives []*ast.Directive) bool {
+	for _, dir := range directives {
+		if dir == nil || dir.Name == nil {
+			continue
+		}
+		switch dir.Name.Value {
+		case graphql.SkipDirective.Name:
+			if skip, _ := c.directiveIf(dir).(bool); skip {
+				return false
+			}
+		case graphql.IncludeDirective.Name:
+			if include, ok := c.directiveIf(dir).(bool); ok && !include {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *fieldsCollector) directiveIf(dir *ast.Directive) interface{} {
+	for _, arg := range dir.Arguments {
+		if arg.Name.Value == "if" {
+			return valueFromAST(arg.Value, graphql.Boolean, c.info.VariableValues)
+		}
+	}
+	return nil
+}
+
+func (c *fieldsCollector) typeCondition(condition *ast.Named, parentType graphql.Type) graphql.Type {
+	if condition == nil {
+		return parentType
+	}
+	return c.info.Schema.Type(condition.Name.Value)
+}
+
+func (c *fieldsCollector) fieldDefinition(parentType graphql.Type, name string) *graphql.FieldDefinition {
+	var fields graphql.FieldDefinitionMap
+	switch parentType := parentType.(type) {
+	case *graphql.Object:
+		fields = parentType.Fields()
+	case *graphql.Interface:
+		fields = parentType.Fields()
+	default:
+		return nil
+	}
+	return fields[name]
+}
+
+func (c *fieldsCollector) argumentValues(fieldDefinition *graphql.FieldDefinition, arguments []*ast.Argument) map[string]interface{} {
+	values := map[string]interface{}{}
+	if fieldDefinition == nil {
+		// unknown type (e.g. union member that is not in schema) - take values as is
+		for _, arg := range arguments {
+			values[arg.Name.Value] = valueFromAST(arg.Value, nil, c.info.VariableValues)
+		}
+		return values
+	}
+
+	argASTs := map[string]*ast.Argument{}
+	for _, arg := range arguments {
+		argASTs[arg.Name.Value] = arg
+	}
+	for _, argDefinition := range fieldDefinition.Args {
+		var value interface{}
+		if arg, has := argASTs[argDefinition.PrivateName]; has {
+			value = valueFromAST(arg.Value, argDefinition.Type, c.info.VariableValues)
+		}
+		if value == nil {
+			value = argDefinition.DefaultValue
+		}
+		if value != nil {
+			values[argDefinition.PrivateName] = value
+		}
+	}
+	return values
+}
+
+func namedType(ttype graphql.Type) graphql.Type {
+	for {
+		switch t := ttype.(type) {
+		case *graphql.NonNull:
+			ttype = t.OfType
+		case *graphql.List:
+			ttype = t.OfType
+		default:
+			return ttype
+		}
+	}
+}
+
+func appendPath(path []string, name string) []string {
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, name)
+}
+
+// LiteralValue converts literal of unknown type to go value: objects to map[string]interface{},
+// lists to []interface{} and enums to their names. Variables are converted to nil
+func LiteralValue(valueAST ast.Value) interface{} {
+	return valueFromAST(valueAST, nil, nil)
+}
+
+// CoerceLiteral converts valid literal of input type to go value the same way as graphql coerces arguments:
+// enums and scalars are parsed, missing input object fields take default values and single value becomes list of one
+func CoerceLiteral(valueAST ast.Value, ttype graphql.Input) interface{} {
+	return valueFromAST(valueAST, ttype, nil)
+}
+
+// valueFromAST converts literal to go value using input type. When ttype is nil (unknown) value converted as is:
+// enums are taken by name and numbers keep its literal kind
+func valueFromAST(valueAST ast.Value, ttype graphql.Input, variables map[string]interface{}) interface{} {
+	if valueAST == nil {
+		return nil
+	}
+	if variable, ok := valueAST.(*ast.Variable); ok {
+		return variables[variable.Name.Value]
+	}
+
+	switch ttype := ttype.(type) {
+	case *graphql.NonNull:
+		return valueFromAST(valueAST, ttype.OfType, variables)
+	case *graphql.List:
+		if listValue, ok := valueAST.(*ast.ListValue); ok {
+			values := make([]interface{}, len(listValue.Values))
+			for i, itemAST := range listValue.Values {
+				values[i] = valueFromAST(itemAST, ttype.OfType, variables)
+			}
+			return values
+		}
+		return []interface{}{valueFromAST(valueAST, ttype.OfType, variables)}
+	case *graphql.InputObject:
+		objectValue, ok := valueAST.(*ast.ObjectValue)
+		if !ok {
+			return nil
+		}
+		fieldASTs := map[string]*ast.ObjectField{}
+		for _, field := range objectValue.Fields {
+			fieldASTs[field.Name.Value] = field
+		}
+		obj := map[string]interface{}{}
+		for name, field := range ttype.Fields() {
+			var value interface{}
+			if fieldAST, has := fieldASTs[name]; has {
+				value = valueFromAST(fieldAST.Value, field.Type, variables)
+			} else {
+				value = field.DefaultValue
+			}
+			if value != nil {
+				obj[name] = value
+			}
+		}
+		return obj
+	case *graphql.Scalar:
+		return ttype.ParseLiteral(valueAST)
+	case *graphql.Enum:
+		return ttype.ParseLiteral(valueAST)
+	}
+
+	// untyped
+	switch valueAST := valueAST.(type) {
+	case *ast.ListValue:
+		values := make([]interface{}, len(valueAST.Values))
+		for i, itemAST := range valueAST.Values {
+			values[i] = valueFromAST(itemAST, nil, variables)
+		}
+		return values
+	case *ast.ObjectValue:
+		obj := map[string]interface{}{}
+		for _, field := range valueAST.Fields {
+			obj[field.Name.Value] = valueFromAST(field.Value, nil, variables)
+		}
+		return obj
+	case *ast.IntValue:
+		return graphql.Int.ParseLiteral(valueAST)
+	case *ast.FloatValue:
+		return graphql.Float.ParseLiteral(valueAST)
+	}
+	return valueAST.GetValue()
+}
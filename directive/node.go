@@ -0,0 +1,25 @@
+package directive
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// EncodeGlobalID makes opaque global ID of object of type typeName (see @node) from its id
+func EncodeGlobalID(typeName string, id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(typeName + ":" + id))
+}
+
+// DecodeGlobalID returns type name and id of object encoded by EncodeGlobalID
+func DecodeGlobalID(globalID string) (typeName string, id string, err error) {
+	decoded, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid global id: %s", globalID)
+	}
+	typeName, id, found := strings.Cut(string(decoded), ":")
+	if !found || typeName == "" {
+		return "", "", fmt.Errorf("invalid global id: %s", globalID)
+	}
+	return typeName, id, nil
+}
@@ -0,0 +1,89 @@
+package directive
+
+import (
+	"context"
+	"sort"
+)
+
+// Phase of field resolving in which directive is executed.
+// Phases are executed in order: PhaseBeforeResolve, PhaseResolve, PhaseAfterResolve.
+// PhaseOnError is executed only when some directive of other phases returned an error.
+type Phase int
+
+const (
+	PhaseBeforeResolve Phase = iota // auth, arguments rewriting, cache lookup
+	PhaseResolve                    // fetch or compute value, default phase
+	PhaseAfterResolve               // formatting, masking
+	PhaseOnError                    // fallbacks, errors mapping
+)
+
+// Phased can be implemented by Directive to be executed in specific phase.
+// Directives without this method are executed in PhaseResolve.
+//
+// ErrStopExecutionWithoutError returned from directive works like "break" for directives of the same phase:
+// rest of the phase is skipped and execution continues with the next phase (e.g. cache hit in PhaseBeforeResolve
+// skips PhaseResolve, but value is still formatted in PhaseAfterResolve).
+type Phased interface {
+	Phase() Phase
+}
+
+// Prioritized can be implemented by Directive to change order of execution within the phase.
+// Directives with greater priority are executed earlier, default priority is 0.
+// Directives with equal priority are executed in order of schema definition.
+type Prioritized interface {
+	Priority() int
+}
+
+// PhaseOf returns phase in which dir should be executed
+func PhaseOf(dir Directive) Phase {
+	if phased, ok := dir.(Phased); ok {
+		return phased.Phase()
+	}
+	return PhaseResolve
+}
+
+// PriorityOf returns priority of dir within its phase
+func PriorityOf(dir Directive) int {
+	if prioritized, ok := dir.(Prioritized); ok {
+		return prioritized.Priority()
+	}
+	return 0
+}
+
+// SortByPhase returns copy of directives ordered by phase and priority, so they can be executed one by one
+func SortByPhase(directives []Directive) []Directive {
+	sorted := make([]Directive, len(directives))
+	copy(sorted, directives)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iPhase, jPhase := PhaseOf(sorted[i]), PhaseOf(sorted[j])
+		if iPhase != jPhase {
+			return iPhase < jPhase
+		}
+		return PriorityOf(sorted[i]) > PriorityOf(sorted[j])
+	})
+	return sorted
+}
+
+type errorContextKey struct{}
+
+// ContextWithError returns copy of ctx that carries error for directives of PhaseOnError
+func ContextWithError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, errorContextKey{}, err)
+}
+
+// ErrorFromContext returns error that caused execution of PhaseOnError directives.
+// Directive of PhaseOnError can return nil error to recover with resolved value
+// or return another error that will be passed to the next PhaseOnError directive.
+func ErrorFromContext(ctx context.Context) error {
+	err, _ := ctx.Value(errorContextKey{}).(error)
+	return err
+}
+
+// FieldsScoped can be implemented by Directive placed on SCHEMA to be executed for every field of every type
+// instead of being executed once per request. Directives placed on OBJECT are always executed for every field of the type.
+//
+// Field can override inherited directive by using directive with the same name
+// or opt out of inherited directives with @noInherit(directives: [String!]) directive.
+type FieldsScoped interface {
+	AppliesToFields() bool
+}
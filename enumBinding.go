@@ -0,0 +1,115 @@
+package actograph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/actord/actograph/directive"
+)
+
+// BindEnum binds every value of enum declared in schema to Go value of type T, usually typed constant:
+//
+//	actograph.BindEnum(agh, "Status", map[string]Status{"ACTIVE": StatusActive, "BLOCKED": StatusBlocked})
+//
+// Enum arguments are passed to directives as T and resolved values of type T are serialized as enum value names
+func BindEnum[T comparable](agh *Actograph, enumName string, values map[string]T) error {
+	binding := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		binding[name] = value
+	}
+	return agh.bindEnum(enumName, binding)
+}
+
+// bindEnum checks and stores binding of enum values to comparable Go values
+func (agh *Actograph) bindEnum(enumName string, binding map[string]interface{}) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	// values added by extensions should be bound too
+	if err := agh.mergeTypeExtensions(); err != nil {
+		return err
+	}
+	enumDefinition, has := agh.enumDefinitions[enumName]
+	if !has {
+		return fmt.Errorf("enum '%s' is not defined in schema", enumName)
+	}
+	if _, has := agh.enumBindings[enumName]; has {
+		return fmt.Errorf("enum '%s' already bound", enumName)
+	}
+
+	names := make(map[interface{}]string, len(binding))
+	for _, valueDefinition := range enumDefinition.Values {
+		name := valueDefinition.Name.Value
+		value, has := binding[name]
+		if !has {
+			return fmt.Errorf("value %s.%s is not bound", enumName, name)
+		}
+		if sameName, has := names[value]; has {
+			return fmt.Errorf("values %s.%s and %s.%s are bound to the same value %v", enumName, sameName, enumName, name, value)
+		}
+		names[value] = name
+	}
+	for name, value := range binding {
+		if names[value] != name {
+			return fmt.Errorf("enum '%s' has no value %s", enumName, name)
+		}
+	}
+
+	agh.enumBindings[enumName] = binding
+	return nil
+}
+
+// enumValueChecker is the last directive of fields of enum type, it reports resolved values
+// that can't be serialized by enum instead of resolving null
+type enumValueChecker struct {
+	enum *graphql.Enum
+}
+
+func (e *enumValueChecker) Phase() directive.Phase {
+	return directive.PhaseAfterResolve
+}
+
+func (e *enumValueChecker) Priority() int {
+	return math.MinInt
+}
+
+func (e *enumValueChecker) Execute(
+	ctx context.Context,
+	_ interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	_ map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	return resolvedValue, ctx, e.check(resolvedValue)
+}
+
+func (e *enumValueChecker) check(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := e.check(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+	if e.enum.Serialize(value) == nil {
+		return fmt.Errorf("enum '%s' cannot represent value %v of type %T", e.enum.Name(), value, value)
+	}
+	return nil
+}
+
+func (e *enumValueChecker) Define(_ string, _ interface{}) error {
+	return nil
+}
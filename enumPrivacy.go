@@ -0,0 +1,140 @@
+package actograph
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// enumPrivacyDirectiveName is hardcoded directive that limits where enum can be used:
+//
+//	directive @enumPrivacy(backend: Boolean, frontend: Boolean) on ENUM
+//
+// backend only enums can be used only as type of type-system directive arguments and never served in schema,
+// frontend only enums can't be used as type of directive arguments. Enum without @enumPrivacy can be used anywhere
+const enumPrivacyDirectiveName = "enumPrivacy"
+
+type enumPrivacy struct {
+	backend  bool
+	frontend bool
+}
+
+func (p enumPrivacy) backendOnly() bool {
+	return p.backend && !p.frontend
+}
+
+func (p enumPrivacy) frontendOnly() bool {
+	return p.frontend && !p.backend
+}
+
+func (agh *Actograph) getEnumPrivacy(enumDefinition *ast.EnumDefinition) enumPrivacy {
+	privacy := enumPrivacy{}
+	for _, dir := range enumDefinition.Directives {
+		if dir.Name.Value != enumPrivacyDirectiveName {
+			continue
+		}
+		for _, arg := range dir.Arguments {
+			value, ok := arg.Value.(*ast.BooleanValue)
+			if !ok {
+				continue
+			}
+			switch arg.Name.Value {
+			case "backend":
+				privacy.backend = value.Value
+			case "frontend":
+				privacy.frontend = value.Value
+			}
+		}
+	}
+	return privacy
+}
+
+// isBackendEnum reports is name is a backend only enum, such enums are not served in schema
+func (agh *Actograph) isBackendEnum(name string) bool {
+	enumDefinition, has := agh.enumDefinitions[name]
+	return has && agh.getEnumPrivacy(enumDefinition).backendOnly()
+}
+
+// validateEnumPrivacy checks that backend only enums are used only in type-system directive arguments
+// and frontend only enums are not used in directive arguments
+func (agh *Actograph) validateEnumPrivacy() error {
+	checkServed := func(t ast.Type, where string) error {
+		name := namedTypeName(t)
+		if agh.isBackendEnum(name) {
+			return fmt.Errorf("enum '%s' is backend only and can't be used as type of %s", name, where)
+		}
+		return nil
+	}
+
+	checkFields := func(typeName string, fields []*ast.FieldDefinition) error {
+		for _, field := range fields {
+			fieldCoordinate := typeName + "." + field.Name.Value
+			if err := checkServed(field.Type, "field "+fieldCoordinate); err != nil {
+				return err
+			}
+			for _, arg := range field.Arguments {
+				if err := checkServed(arg.Type, "argument "+fieldCoordinate+"("+arg.Name.Value+":)"); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for name, objDefinition := range agh.objectDefinitions {
+		if err := checkFields(name, objDefinition.Fields); err != nil {
+			return err
+		}
+	}
+	for name, extensions := range agh.extensionDefinitions {
+		for _, ext := range extensions {
+			if err := checkFields(name, ext.Definition.Fields); err != nil {
+				return err
+			}
+		}
+	}
+	for name, inputObjDefinition := range agh.inputObjectDefinitions {
+		for _, field := range inputObjDefinition.Fields {
+			if err := checkServed(field.Type, "input field "+name+"."+field.Name.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, dirDefinition := range agh.directiveDefinitions {
+		for _, arg := range dirDefinition.Arguments {
+			argTypeName := namedTypeName(arg.Type)
+			enumDefinition, isEnum := agh.enumDefinitions[argTypeName]
+			if !isEnum {
+				continue
+			}
+			where := "argument @" + name + "(" + arg.Name.Value + ":)"
+			privacy := agh.getEnumPrivacy(enumDefinition)
+			if privacy.frontendOnly() {
+				return fmt.Errorf("enum '%s' is frontend only and can't be used as type of directive %s", argTypeName, where)
+			}
+			if privacy.backendOnly() && isExecutableDirective(dirDefinition) {
+				// executable directives are served in schema
+				return fmt.Errorf("enum '%s' is backend only and can't be used as type of executable directive %s", argTypeName, where)
+			}
+		}
+	}
+
+	return nil
+}
+
+// namedTypeName unwraps list and non-null types and returns name of the named type
+func namedTypeName(t ast.Type) string {
+	for {
+		switch tt := t.(type) {
+		case *ast.NonNull:
+			t = tt.Type
+		case *ast.List:
+			t = tt.Type
+		case *ast.Named:
+			return tt.Name.Value
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/actord/actograph/directive"
+)
+
+// DirectiveFallback resolves "val" when any other directive of the field failed
+type DirectiveFallback struct {
+	val string
+}
+
+func NewDirectiveFallback(args directive.Arguments, nodeKind string) (directive.Directive, error) {
+	val, ok := args["val"]
+	if !ok {
+		return nil, errors.New("val not in arguments")
+	}
+	return &DirectiveFallback{
+		val: val.GetValue().(string),
+	}, nil
+}
+
+func (d *DirectiveFallback) Phase() directive.Phase {
+	return directive.PhaseOnError
+}
+
+func (d *DirectiveFallback) Execute(
+	ctx context.Context,
+	source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	return fmt.Sprintf("%s (%s)", d.val, directive.ErrorFromContext(ctx)), ctx, nil
+}
+
+func (d *DirectiveFallback) Define(_ string, _ interface{}) error {
+	return nil
+}
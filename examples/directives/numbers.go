@@ -0,0 +1,64 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/actord/actograph/directive"
+)
+
+// DirectiveNumbers is a pager for @connection field: it resolves directive.Page of numbers 0...count-1
+// without making the whole list
+type DirectiveNumbers struct {
+	count int
+}
+
+func NewDirectiveNumbers(args directive.Arguments, nodeKind string) (directive.Directive, error) {
+	count, ok := args["count"]
+	if !ok {
+		return nil, errors.New("count not in arguments")
+	}
+	countValue, err := strconv.Atoi(count.GetValue().(string))
+	if err != nil {
+		return nil, err
+	}
+	return &DirectiveNumbers{
+		count: countValue,
+	}, nil
+}
+
+func (d *DirectiveNumbers) Execute(
+	ctx context.Context,
+	source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	args := directive.ConnectionArgsFrom(fieldArgs)
+	offset := 0
+	if args.After != "" {
+		after, err := directive.DecodeCursor(args.After)
+		if err != nil {
+			return nil, ctx, err
+		}
+		offset = after + 1
+	}
+	limit := 10
+	if args.First != nil {
+		limit = *args.First
+	}
+
+	page := directive.Page{
+		Offset:          offset,
+		HasPreviousPage: offset > 0,
+	}
+	for i := offset; i < d.count && i < offset+limit; i++ {
+		page.Nodes = append(page.Nodes, i)
+	}
+	page.HasNextPage = offset+len(page.Nodes) < d.count
+	return page, ctx, nil
+}
+
+func (d *DirectiveNumbers) Define(_ string, _ interface{}) error {
+	return nil
+}
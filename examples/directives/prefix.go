@@ -0,0 +1,45 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/actord/actograph/directive"
+)
+
+// DirectivePrefix adds prefix to resolved value. Placed on type or schema it applies to every field
+type DirectivePrefix struct {
+	val string
+}
+
+func NewDirectivePrefix(args directive.Arguments, nodeKind string) (directive.Directive, error) {
+	val, ok := args["val"]
+	if !ok {
+		return nil, errors.New("val not in arguments")
+	}
+	return &DirectivePrefix{
+		val: val.GetValue().(string),
+	}, nil
+}
+
+func (d *DirectivePrefix) Phase() directive.Phase {
+	return directive.PhaseAfterResolve
+}
+
+func (d *DirectivePrefix) AppliesToFields() bool {
+	return true
+}
+
+func (d *DirectivePrefix) Execute(
+	ctx context.Context,
+	source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	return fmt.Sprintf("%s%v", d.val, resolvedValue), ctx, nil
+}
+
+func (d *DirectivePrefix) Define(_ string, _ interface{}) error {
+	return nil
+}
@@ -0,0 +1,47 @@
+package directives
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/actord/actograph/directive"
+)
+
+// DirectiveRequestedFields resolves object with "requestedFields" key filled with requested child fields
+// in form "path(arg:value,...)", just like sql or rest directive can build projection
+type DirectiveRequestedFields struct{}
+
+func NewDirectiveRequestedFields(args directive.Arguments, nodeKind string) (directive.Directive, error) {
+	return &DirectiveRequestedFields{}, nil
+}
+
+func (d *DirectiveRequestedFields) Execute(
+	ctx context.Context,
+	source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	fields := directive.RequestedFields(ctx)
+	requested := make([]string, len(fields))
+	for i, field := range fields {
+		args := make([]string, 0, len(field.Arguments))
+		for key, value := range field.Arguments {
+			args = append(args, fmt.Sprintf("%s:%v", key, value))
+		}
+		sort.Strings(args)
+		requested[i] = field.PathString()
+		if len(args) > 0 {
+			requested[i] += "(" + strings.Join(args, ",") + ")"
+		}
+	}
+
+	return map[string]interface{}{
+		"requestedFields": requested,
+	}, ctx, nil
+}
+
+func (d *DirectiveRequestedFields) Define(_ string, _ interface{}) error {
+	return nil
+}
@@ -0,0 +1,35 @@
+package directives
+
+import (
+	"context"
+	"strings"
+
+	"github.com/actord/actograph/directive"
+)
+
+// DirectiveUpper formats resolved string to upper case after field is resolved, no matter where it placed in schema
+type DirectiveUpper struct{}
+
+func NewDirectiveUpper(args directive.Arguments, nodeKind string) (directive.Directive, error) {
+	return &DirectiveUpper{}, nil
+}
+
+func (d *DirectiveUpper) Phase() directive.Phase {
+	return directive.PhaseAfterResolve
+}
+
+func (d *DirectiveUpper) Execute(
+	ctx context.Context,
+	source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	if str, ok := resolvedValue.(string); ok {
+		return strings.ToUpper(str), ctx, nil
+	}
+	return resolvedValue, ctx, nil
+}
+
+func (d *DirectiveUpper) Define(_ string, _ interface{}) error {
+	return nil
+}
@@ -0,0 +1,43 @@
+package directives
+
+import (
+	"context"
+	"errors"
+
+	"github.com/actord/actograph/directive"
+)
+
+// DirectiveVisibleIf removes field from response when there is no value by "contextKey" in context
+type DirectiveVisibleIf struct {
+	contextKey string
+}
+
+func NewDirectiveVisibleIf(args directive.Arguments, nodeKind string) (directive.Directive, error) {
+	contextKey, ok := args["contextKey"]
+	if !ok {
+		return nil, errors.New("contextKey not in arguments")
+	}
+	return &DirectiveVisibleIf{
+		contextKey: contextKey.GetValue().(string),
+	}, nil
+}
+
+func (d *DirectiveVisibleIf) Phase() directive.Phase {
+	return directive.PhaseBeforeResolve
+}
+
+func (d *DirectiveVisibleIf) Execute(
+	ctx context.Context,
+	source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	if ctx.Value(d.contextKey) == nil {
+		return nil, ctx, directive.ErrOmitField
+	}
+	return resolvedValue, ctx, nil
+}
+
+func (d *DirectiveVisibleIf) Define(_ string, _ interface{}) error {
+	return nil
+}
@@ -0,0 +1,39 @@
+package scalars
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is registered by actograph.RegisterGoScalar[Money] as string like "12.50 USD"
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalText() ([]byte, error) {
+	sign := ""
+	cents := m.Cents
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return []byte(fmt.Sprintf("%s%d.%02d %s", sign, cents/100, cents%100, m.Currency)), nil
+}
+
+func (m *Money) UnmarshalText(text []byte) error {
+	amount, currency, found := strings.Cut(string(text), " ")
+	if !found || len(currency) != 3 {
+		return fmt.Errorf("invalid money %q, expected amount and currency like \"12.50 USD\"", text)
+	}
+	units, fraction, _ := strings.Cut(amount, ".")
+	if len(fraction) > 2 {
+		return fmt.Errorf("invalid money %q, at most 2 fraction digits allowed", text)
+	}
+	cents, err := strconv.ParseInt(units+(fraction + "00")[:2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid money %q: %w", text, err)
+	}
+	m.Cents, m.Currency = cents, currency
+	return nil
+}
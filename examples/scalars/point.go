@@ -0,0 +1,24 @@
+package scalars
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Point is registered by actograph.RegisterGoScalar[Point] as JSON list [x, y]
+type Point struct {
+	X, Y float64
+}
+
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{p.X, p.Y})
+}
+
+func (p *Point) UnmarshalJSON(data []byte) error {
+	var coordinates []float64
+	if err := json.Unmarshal(data, &coordinates); err != nil || len(coordinates) != 2 {
+		return fmt.Errorf("invalid point %s, expected [x, y]", data)
+	}
+	p.X, p.Y = coordinates[0], coordinates[1]
+	return nil
+}
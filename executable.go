@@ -0,0 +1,229 @@
+package actograph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// errBuilt is returned when built actograph is changed
+var errBuilt = errors.New("actograph is already built and can't be changed")
+
+// otherAudience is audience of schema variant served for audiences not listed by any @visibility,
+// such audiences see the same elements
+const otherAudience = "\x00other"
+
+// Executable is actograph compiled by Actograph.Build with all variants of schema. It is immutable and safe
+// for concurrent use
+type Executable struct {
+	agh     *Actograph
+	schemas map[schemaVariant]*graphql.Schema
+	// audiences listed by @visibility, other audiences are served by otherAudience variant
+	audiences map[string]bool
+	// execute wrapped by middlewares registered with Use
+	handler Handler
+	// URLs of scalar specifications, see SpecifiedByURL
+	specifiedByURLs map[string]string
+}
+
+// Build validates schema and makes every variant of it (see SchemaFor). Actograph can't be changed after build:
+// registration functions return error. Build returns the same Executable when called again
+func (agh *Actograph) Build() (*Executable, error) {
+	if exe := agh.executable.Load(); exe != nil {
+		return exe, nil
+	}
+	agh.buildMu.Lock()
+	defer agh.buildMu.Unlock()
+	if exe := agh.executable.Load(); exe != nil {
+		return exe, nil
+	}
+
+	exe := &Executable{
+		agh:       agh,
+		schemas:   map[schemaVariant]*graphql.Schema{},
+		audiences: map[string]bool{},
+	}
+	exe.handler = chainMiddlewares(agh.middlewares, exe.execute)
+	exe.specifiedByURLs = agh.specifiedByURLs()
+	variants := []schemaVariant{{}}
+	audiences := agh.visibilityAudiences()
+	if len(audiences) > 0 {
+		variants = append(variants, schemaVariant{audience: otherAudience})
+	}
+	for _, audience := range audiences {
+		exe.audiences[audience] = true
+		variants = append(variants, schemaVariant{audience: audience})
+	}
+
+	for _, variant := range variants {
+		schema, err := agh.makeVariant(variant)
+		if err != nil {
+			return nil, err
+		}
+		warmUpSchema(schema)
+		exe.schemas[variant] = &schema
+
+		if !agh.hasInternal {
+			continue
+		}
+		variant.introspection = true
+		introspectionSchema, err := agh.makeVariant(variant)
+		if err != nil {
+			return nil, err
+		}
+		warmUpSchema(introspectionSchema)
+		exe.schemas[variant] = &introspectionSchema
+	}
+
+	agh.executable.Store(exe)
+	return exe, nil
+}
+
+// warmUpSchema fills lookup maps that graphql enums make on first use, so concurrent requests only read them
+func warmUpSchema(schema graphql.Schema) {
+	for _, t := range schema.TypeMap() {
+		if enum, ok := t.(*graphql.Enum); ok {
+			enum.Serialize(nil)
+			enum.ParseValue("")
+		}
+	}
+}
+
+// checkNotBuilt returns error when actograph is built, see Build
+func (agh *Actograph) checkNotBuilt() error {
+	if agh.executable.Load() != nil {
+		return errBuilt
+	}
+	return nil
+}
+
+// variant returns schema variant served for audience
+func (exe *Executable) variant(audience string, introspection bool) schemaVariant {
+	if audience != "" && !exe.audiences[audience] {
+		audience = otherAudience
+		if len(exe.audiences) == 0 {
+			// without @visibility every audience sees full schema
+			audience = ""
+		}
+	}
+	return schemaVariant{audience: audience, introspection: introspection}
+}
+
+// Schema returns full schema
+func (exe *Executable) Schema() graphql.Schema {
+	return *exe.schemas[schemaVariant{}]
+}
+
+// SchemaFor returns variant of schema for audience, see Actograph.SchemaFor
+func (exe *Executable) SchemaFor(audience string) graphql.Schema {
+	return *exe.schemas[exe.variant(audience, false)]
+}
+
+// SpecifiedByURL returns URL of specification of scalar set by @specifiedBy or ScalarConfig.SpecifiedByURL
+func (exe *Executable) SpecifiedByURL(scalarName string) (string, bool) {
+	url, has := exe.specifiedByURLs[scalarName]
+	return url, has
+}
+
+// Handler returns http.Handler executing requests, see Actograph.Handler
+func (exe *Executable) Handler(cfg HandlerConfig) http.Handler {
+	return newHandler(cfg, exe.Do)
+}
+
+// Do executes request by middlewares registered with Use and executable
+func (exe *Executable) Do(request RequestQuery) (*Result, error) {
+	ctx := request.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	result := exe.handler(ctx, request)
+	if result == nil {
+		return nil, fmt.Errorf("unknown result")
+	}
+	return result, nil
+}
+
+// execute executes request with schema directives, it is the innermost Handler
+func (exe *Executable) execute(ctx context.Context, request RequestQuery) *Result {
+	agh := exe.agh
+	schema := exe.SchemaFor(request.Audience)
+	var err error
+
+	var state *requestState
+	ctx, state = contextWithRequestState(ctx)
+
+	var rootObject map[string]interface{}
+	if request.RootObject == nil {
+		rootObject = map[string]interface{}{}
+	} else {
+		rootObject = request.RootObject
+	}
+
+	var resolvedValue interface{}
+	resolvedValue, ctx, err = agh.executeDirectives(ctx, rootObject, rootObject, map[string]interface{}{}, agh.lazySchemaDirectives)
+	// schema directives should return map[string]interface{}
+	if resolvedValueMap, ok := resolvedValue.(map[string]interface{}); ok {
+		rootObject = resolvedValueMap
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(request.RequestString),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return &Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validationResult := graphql.ValidateDocument(&schema, doc, agh.validationRules(ctx))
+	if !validationResult.IsValid {
+		return &Result{Errors: validationResult.Errors}
+	}
+	if errs := agh.variableErrors(&schema, doc, request.OperationName, request.VariableValues); len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+
+	execute := func(schema graphql.Schema, doc *ast.Document) *graphql.Result {
+		return graphql.Execute(graphql.ExecuteParams{
+			Schema:        schema,
+			Root:          rootObject,
+			AST:           doc,
+			OperationName: request.OperationName,
+			Args:          request.VariableValues,
+			Context:       ctx,
+		})
+	}
+	var result *graphql.Result
+	if !agh.hasInternal {
+		result = execute(schema, doc)
+	} else {
+		// introspection fields are served by schema without @internal elements
+		introspectionSchema := *exe.schemas[exe.variant(request.Audience, true)]
+		introspectionDoc, otherDoc := splitIntrospection(doc, request.OperationName)
+		switch {
+		case introspectionDoc == nil:
+			result = execute(schema, doc)
+		case otherDoc == nil:
+			result = execute(introspectionSchema, introspectionDoc)
+		default:
+			result = mergeResults(execute(schema, otherDoc), execute(introspectionSchema, introspectionDoc))
+		}
+	}
+	if result == nil {
+		return nil
+	}
+
+	return &Result{
+		Data:       omitFields(result.Data, state.omitted),
+		Errors:     result.Errors,
+		Extensions: result.Extensions,
+	}
+}
@@ -0,0 +1,364 @@
+package actograph
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph/directive"
+)
+
+// executableDirectiveLocations are locations of directives that clients write in operations
+var executableDirectiveLocations = map[string]bool{
+	graphql.DirectiveLocationQuery:          true,
+	graphql.DirectiveLocationMutation:       true,
+	graphql.DirectiveLocationSubscription:   true,
+	graphql.DirectiveLocationField:          true,
+	graphql.DirectiveLocationFragmentSpread: true,
+	graphql.DirectiveLocationInlineFragment: true,
+}
+
+func isExecutableDirective(definition *ast.DirectiveDefinition) bool {
+	for _, location := range definition.Locations {
+		if executableDirectiveLocations[location.Value] {
+			return true
+		}
+	}
+	return false
+}
+
+// makeExecutableDirectives declares directives with executable locations for graphql schema, so clients can use them
+// in operations. Returned types are types of directive arguments that should be added to schema types
+func (agh *Actograph) makeExecutableDirectives() ([]*graphql.Directive, []graphql.Type) {
+	names := agh.executableDirectiveNames()
+	directives := append([]*graphql.Directive{}, graphql.SpecifiedDirectives...)
+	var argTypes []graphql.Type
+	for _, name := range names {
+		definition := agh.directiveDefinitions[name]
+
+		locations := make([]string, len(definition.Locations))
+		for i, location := range definition.Locations {
+			locations[i] = location.Value
+		}
+
+		args := graphql.FieldConfigArgument{}
+		for _, argDefinition := range definition.Arguments {
+			argType := agh.getType(argDefinition.Type)
+			var description string
+			if argDefinition.Description != nil {
+				description = argDefinition.Description.Value
+			}
+			args[argDefinition.Name.Value] = &graphql.ArgumentConfig{
+				Type:         argType,
+				DefaultValue: agh.coerceDefaultValue(argType, argDefinition),
+				Description:  description,
+			}
+			argTypes = append(argTypes, graphql.GetNamed(argType).(graphql.Type))
+		}
+
+		var description string
+		if definition.Description != nil {
+			description = definition.Description.Value
+		}
+
+		directives = append(directives, graphql.NewDirective(graphql.DirectiveConfig{
+			Name:        name,
+			Description: description,
+			Locations:   locations,
+			Args:        args,
+		}))
+	}
+
+	return directives, argTypes
+}
+
+// executableDirectiveNames returns sorted names of directives clients can use in operations for schema variant
+func (agh *Actograph) executableDirectiveNames() []string {
+	specified := map[string]bool{}
+	for _, dir := range graphql.SpecifiedDirectives {
+		specified[dir.Name] = true
+	}
+
+	names := make([]string, 0, len(agh.directiveDefinitions))
+	for name, definition := range agh.directiveDefinitions {
+		if !specified[name] && isExecutableDirective(definition) && agh.isDirectiveVisible(definition) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isDirectiveVisible reports are all argument types of directive served in schema variant of agh.audience
+func (agh *Actograph) isDirectiveVisible(definition *ast.DirectiveDefinition) bool {
+	for _, arg := range definition.Arguments {
+		if !agh.isTypeVisible(arg.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// directiveUsage is a directive written in operation with the node it's written on
+type directiveUsage struct {
+	usage *ast.Directive
+	node  ast.Node
+}
+
+// requestDirectives keeps executable directives of single request, it's part of requestState
+type requestDirectives struct {
+	once sync.Once
+
+	// directives of enclosing operation and fragments by response key path and field node
+	enclosing map[string]map[*ast.Field][]directiveUsage
+
+	mx          sync.Mutex
+	constructed map[*ast.Directive]directive.Directive
+}
+
+// fieldExecutableDirectives returns constructed directives that client wrote in operation for currently resolving field:
+// directives of operation (for root fields), enclosing fragments and the field itself
+func (agh *Actograph) fieldExecutableDirectives(ctx context.Context, info graphql.ResolveInfo) ([]directive.Directive, error) {
+	state := requestStateFromContext(ctx)
+	if state == nil {
+		return nil, nil
+	}
+	rd := &state.directives
+	var directives []directive.Directive
+	for _, usage := range rd.fieldUsages(info) {
+		dir, err := rd.construct(agh, usage.usage, usage.node, info.VariableValues)
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, dir)
+	}
+	return directives, nil
+}
+
+// hasFieldExecutableDirectives reports did client write directives (except @skip and @include)
+// for currently resolving field, see fieldExecutableDirectives
+func (agh *Actograph) hasFieldExecutableDirectives(ctx context.Context, info graphql.ResolveInfo) bool {
+	state := requestStateFromContext(ctx)
+	return state != nil && len(state.directives.fieldUsages(info)) > 0
+}
+
+// fieldUsages returns usages of directives (except @skip and @include) that client wrote for field
+func (rd *requestDirectives) fieldUsages(info graphql.ResolveInfo) []directiveUsage {
+	rd.once.Do(func() {
+		rd.constructed = map[*ast.Directive]directive.Directive{}
+		rd.enclosing = map[string]map[*ast.Field][]directiveUsage{}
+		if operation, ok := info.Operation.(*ast.OperationDefinition); ok {
+			enclosing := appendDirectives(nil, operation, operation.Directives)
+			rd.collectEnclosing(operation.SelectionSet, "", enclosing, info.Fragments, map[string]bool{})
+		}
+	})
+
+	keyPath := responseKeyPath(info.Path)
+	var usages []directiveUsage
+	for _, fieldAST := range info.FieldASTs {
+		for _, usage := range rd.enclosing[keyPath][fieldAST] {
+			if !isConditionDirective(usage.usage) {
+				usages = append(usages, usage)
+			}
+		}
+		for _, usage := range fieldAST.Directives {
+			if !isConditionDirective(usage) {
+				usages = append(usages, directiveUsage{usage: usage, node: fieldAST})
+			}
+		}
+	}
+	return usages
+}
+
+// isConditionDirective reports is directive @skip or @include, they are handled by graphql
+func isConditionDirective(usage *ast.Directive) bool {
+	name := usage.Name.Value
+	return name == graphql.SkipDirective.Name || name == graphql.IncludeDirective.Name
+}
+
+// collectEnclosing walks selection set and remembers directives of operation and fragments enclosing every field
+func (rd *requestDirectives) collectEnclosing(
+	selectionSet *ast.SelectionSet,
+	keyPath string,
+	enclosing []directiveUsage,
+	fragments map[string]ast.Definition,
+	visitedFragments map[string]bool,
+) {
+	if selectionSet == nil {
+		return
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			responseKey := selection.Name.Value
+			if selection.Alias != nil {
+				responseKey = selection.Alias.Value
+			}
+			fieldKeyPath := keyPath + "." + responseKey
+			if len(enclosing) > 0 {
+				if rd.enclosing[fieldKeyPath] == nil {
+					rd.enclosing[fieldKeyPath] = map[*ast.Field][]directiveUsage{}
+				}
+				rd.enclosing[fieldKeyPath][selection] = append(rd.enclosing[fieldKeyPath][selection], enclosing...)
+			}
+			rd.collectEnclosing(selection.SelectionSet, fieldKeyPath, nil, fragments, map[string]bool{})
+		case *ast.InlineFragment:
+			rd.collectEnclosing(selection.SelectionSet, keyPath, appendDirectives(enclosing, selection, selection.Directives), fragments, visitedFragments)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := fragments[name].(*ast.FragmentDefinition)
+			if !ok || visitedFragments[name] {
+				continue
+			}
+			visitedFragments[name] = true
+			rd.collectEnclosing(fragment.SelectionSet, keyPath, appendDirectives(enclosing, selection, selection.Directives), fragments, visitedFragments)
+			delete(visitedFragments, name)
+		}
+	}
+}
+
+func (rd *requestDirectives) construct(
+	agh *Actograph,
+	usage *ast.Directive,
+	node ast.Node,
+	variables map[string]interface{},
+) (directive.Directive, error) {
+	rd.mx.Lock()
+	defer rd.mx.Unlock()
+
+	if dir, has := rd.constructed[usage]; has {
+		return dir, nil
+	}
+
+	name := usage.Name.Value
+	declaration, has := agh.directiveDeclarations[name]
+	if !has {
+		return nil, fmt.Errorf("undefined declaration for directive @%s", name)
+	}
+	definition := agh.directiveDefinitions[name]
+	arguments := agh.makeDirectiveArguments(usage, definition)
+	for key, value := range arguments {
+		if value = substituteVariables(value, variables); value != nil {
+			arguments[key] = value
+			continue
+		}
+		// variable is not provided - use default value if any
+		delete(arguments, key)
+		for _, argDefinition := range definition.Arguments {
+			if argDefinition.Name.Value == key && argDefinition.DefaultValue != nil {
+				arguments[key] = argDefinition.DefaultValue
+			}
+		}
+	}
+
+	dir, err := declaration.Construct(arguments, node)
+	if err != nil {
+		return nil, fmt.Errorf("cant construct directive usage for @%s: %w", name, err)
+	}
+	rd.constructed[usage] = dir
+	return dir, nil
+}
+
+// appendDirectives returns copy of usages with directives written on node appended
+func appendDirectives(usages []directiveUsage, node ast.Node, directives []*ast.Directive) []directiveUsage {
+	if len(directives) == 0 {
+		return usages
+	}
+	result := make([]directiveUsage, 0, len(usages)+len(directives))
+	result = append(result, usages...)
+	for _, dir := range directives {
+		result = append(result, directiveUsage{usage: dir, node: node})
+	}
+	return result
+}
+
+// responseKeyPath returns response path without list indexes, like ".user.friends.name"
+func responseKeyPath(path *graphql.ResponsePath) string {
+	var keys []string
+	for ; path != nil; path = path.Prev {
+		if key, ok := path.Key.(string); ok {
+			keys = append(keys, key)
+		}
+	}
+	var sb strings.Builder
+	for i := len(keys) - 1; i >= 0; i-- {
+		sb.WriteString(".")
+		sb.WriteString(keys[i])
+	}
+	return sb.String()
+}
+
+// substituteVariables replaces variables in value with literals made from variables values,
+// so directive constructors get all argument values as literals
+func substituteVariables(value ast.Value, variables map[string]interface{}) ast.Value {
+	switch value := value.(type) {
+	case *ast.Variable:
+		return astFromValue(variables[value.Name.Value])
+	case *ast.ListValue:
+		values := make([]ast.Value, len(value.Values))
+		for i, item := range value.Values {
+			values[i] = substituteVariables(item, variables)
+		}
+		return ast.NewListValue(&ast.ListValue{Values: values, Loc: value.Loc})
+	case *ast.ObjectValue:
+		fields := make([]*ast.ObjectField, len(value.Fields))
+		for i, field := range value.Fields {
+			fields[i] = ast.NewObjectField(&ast.ObjectField{
+				Name:  field.Name,
+				Value: substituteVariables(field.Value, variables),
+				Loc:   field.Loc,
+			})
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{Fields: fields, Loc: value.Loc})
+	}
+	return value
+}
+
+// astFromValue makes literal from go value
+func astFromValue(value interface{}) ast.Value {
+	if value == nil {
+		return nil
+	}
+	switch value := value.(type) {
+	case ast.Value:
+		return value
+	case string:
+		return ast.NewStringValue(&ast.StringValue{Value: value})
+	case bool:
+		return ast.NewBooleanValue(&ast.BooleanValue{Value: value})
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return ast.NewIntValue(&ast.IntValue{Value: fmt.Sprintf("%d", value)})
+	case float32, float64:
+		return ast.NewFloatValue(&ast.FloatValue{Value: fmt.Sprintf("%v", value)})
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]*ast.ObjectField, 0, len(keys))
+		for _, key := range keys {
+			fields = append(fields, ast.NewObjectField(&ast.ObjectField{
+				Name:  ast.NewName(&ast.Name{Value: key}),
+				Value: astFromValue(value[key]),
+			}))
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{Fields: fields})
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		values := make([]ast.Value, rv.Len())
+		for i := range values {
+			values[i] = astFromValue(rv.Index(i).Interface())
+		}
+		return ast.NewListValue(&ast.ListValue{Values: values})
+	}
+	return ast.NewStringValue(&ast.StringValue{Value: fmt.Sprintf("%v", value)})
+}
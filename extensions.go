@@ -0,0 +1,402 @@
+package actograph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/lexer"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// typeExtension is `extend input|enum|union|interface|scalar|schema` definition. graphql parser supports only
+// `extend type`, so other extensions are parsed as definitions of the same kind, and extensions without body
+// (only directives are added) are parsed as scalar definitions, see markExtensions
+type typeExtension struct {
+	kind string // keyword of extended kind
+	node ast.Node
+}
+
+// memberKinds names members added by extensions of every kind
+var memberKinds = map[string]string{
+	lexer.TYPE:      "field",
+	lexer.INPUT:     "field",
+	lexer.INTERFACE: "field",
+	lexer.ENUM:      "value",
+	lexer.UNION:     "member type",
+	lexer.SCHEMA:    "operation type",
+	lexer.SCALAR:    "",
+}
+
+// markedExtension is extension rewritten by markExtensions
+type markedExtension struct {
+	kind string
+	// interfaces implemented by `extend type Name implements Interface` without fields
+	interfaces []string
+}
+
+// markExtensions rewrites extensions that graphql parser doesn't support without moving other tokens, so
+// locations stay the same: `extend` keyword is replaced by spaces and extensions without body become
+// `scalar Name @directives`. Returned map has extensions by start positions of parsed definitions.
+// Body with syntax errors is returned as is, so parser reports them
+func markExtensions(body []byte) ([]byte, map[int]markedExtension) {
+	var tokens []lexer.Token
+	lex := lexer.Lex(source.NewSource(&source.Source{Body: body}))
+	for {
+		token, err := lex(0)
+		if err != nil {
+			return body, nil
+		}
+		if token.Kind == lexer.EOF {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+
+	marked := body
+	starts := map[int]markedExtension{}
+	depth := 0
+	for i, token := range tokens {
+		switch token.Kind {
+		case lexer.BRACE_L, lexer.PAREN_L, lexer.BRACKET_L:
+			depth++
+			continue
+		case lexer.BRACE_R, lexer.PAREN_R, lexer.BRACKET_R:
+			depth--
+			continue
+		}
+		if depth != 0 || token.Kind != lexer.NAME || token.Value != lexer.EXTEND || i+1 == len(tokens) {
+			continue
+		}
+		kindToken := tokens[i+1]
+		if _, has := memberKinds[kindToken.Value]; !has || kindToken.Kind != lexer.NAME {
+			continue
+		}
+		if token.End > len(body) || string(body[token.Start:token.End]) != lexer.EXTEND {
+			// positions don't match bytes of body, parser reports unsupported extension
+			continue
+		}
+		hasBody, implements := scanExtension(kindToken.Value, tokens[i+2:])
+		if kindToken.Value == lexer.TYPE && hasBody {
+			continue
+		}
+		if len(starts) == 0 {
+			marked = append([]byte{}, body...)
+		}
+
+		if hasBody || kindToken.Value == lexer.SCALAR {
+			blank(marked[token.Start:token.End])
+			starts[kindToken.Start] = markedExtension{kind: kindToken.Value}
+			continue
+		}
+		copy(marked[token.Start:token.End], lexer.SCALAR)
+		if kindToken.Value != lexer.SCHEMA {
+			blank(marked[kindToken.Start:kindToken.End])
+		}
+		extension := markedExtension{kind: kindToken.Value}
+		for _, implementsToken := range implements {
+			if implementsToken.Kind == lexer.NAME && implementsToken.Value != "implements" {
+				extension.interfaces = append(extension.interfaces, implementsToken.Value)
+			}
+			blank(marked[implementsToken.Start:implementsToken.End])
+		}
+		starts[token.Start] = extension
+	}
+	return marked, starts
+}
+
+// scanExtension reports are there fields, values, union members or operation types in extension and returns
+// tokens of `implements` clause. Tokens start after keyword of extended kind
+func scanExtension(kind string, tokens []lexer.Token) (hasBody bool, implements []lexer.Token) {
+	i := 0
+	if kind != lexer.SCHEMA {
+		i++ // name of extended type
+	}
+	for i < len(tokens) {
+		token := tokens[i]
+		switch {
+		case token.Kind == lexer.BRACE_L || token.Kind == lexer.EQUALS:
+			return true, nil
+		case token.Kind == lexer.AT:
+			i += 2 // @ and name of directive
+			if i < len(tokens) && tokens[i].Kind == lexer.PAREN_L {
+				for depth := 0; i < len(tokens); i++ {
+					if tokens[i].Kind == lexer.PAREN_L {
+						depth++
+					} else if tokens[i].Kind == lexer.PAREN_R {
+						depth--
+					}
+					if depth == 0 {
+						break
+					}
+				}
+				i++
+			}
+		case token.Kind == lexer.NAME && token.Value == "implements" && i+1 < len(tokens):
+			start := i
+			i++
+			if tokens[i].Kind == lexer.AMP {
+				i++ // optional leading ampersand
+			}
+			i++
+			for i+1 < len(tokens) && tokens[i].Kind == lexer.AMP {
+				i += 2
+			}
+			if i > len(tokens) {
+				i = len(tokens)
+			}
+			implements = append(implements, tokens[start:i]...)
+		default:
+			return false, implements
+		}
+	}
+	return false, implements
+}
+
+func blank(b []byte) {
+	for i := range b {
+		b[i] = ' '
+	}
+}
+
+func (agh *Actograph) addTypeExtension(extension markedExtension, node ast.Node) {
+	if extension.kind != lexer.TYPE {
+		agh.typeExtensions = append(agh.typeExtensions, typeExtension{kind: extension.kind, node: node})
+		return
+	}
+	// extension of object without fields
+	scalar := node.(*ast.ScalarDefinition)
+	interfaces := make([]*ast.Named, len(extension.interfaces))
+	for i, name := range extension.interfaces {
+		interfaces[i] = named(name)
+	}
+	agh.addExtensionDefinition(ast.NewTypeExtensionDefinition(&ast.TypeExtensionDefinition{
+		Loc: scalar.Loc,
+		Definition: ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Loc:        scalar.Loc,
+			Name:       scalar.Name,
+			Directives: scalar.Directives,
+			Interfaces: interfaces,
+		}),
+	}))
+}
+
+// definitionParts are name, members and directives of definition or extension
+type definitionParts struct {
+	name       string
+	members    []string
+	directives []*ast.Directive
+	loc        *ast.Location
+}
+
+func partsOf(node ast.Node) definitionParts {
+	var parts definitionParts
+	switch node := node.(type) {
+	case *ast.ObjectDefinition:
+		parts = definitionParts{name: node.Name.Value, directives: node.Directives, loc: node.Loc}
+		for _, field := range node.Fields {
+			parts.members = append(parts.members, field.Name.Value)
+		}
+	case *ast.InputObjectDefinition:
+		parts = definitionParts{name: node.Name.Value, directives: node.Directives, loc: node.Loc}
+		for _, field := range node.Fields {
+			parts.members = append(parts.members, field.Name.Value)
+		}
+	case *ast.InterfaceDefinition:
+		parts = definitionParts{name: node.Name.Value, directives: node.Directives, loc: node.Loc}
+		for _, field := range node.Fields {
+			parts.members = append(parts.members, field.Name.Value)
+		}
+	case *ast.EnumDefinition:
+		parts = definitionParts{name: node.Name.Value, directives: node.Directives, loc: node.Loc}
+		for _, value := range node.Values {
+			parts.members = append(parts.members, value.Name.Value)
+		}
+	case *ast.UnionDefinition:
+		parts = definitionParts{name: node.Name.Value, directives: node.Directives, loc: node.Loc}
+		for _, member := range node.Types {
+			parts.members = append(parts.members, member.Name.Value)
+		}
+	case *ast.SchemaDefinition:
+		parts = definitionParts{directives: node.Directives, loc: node.Loc}
+		for _, ot := range node.OperationTypes {
+			parts.members = append(parts.members, ot.Operation)
+		}
+	case *ast.ScalarDefinition:
+		parts = definitionParts{name: node.Name.Value, directives: node.Directives, loc: node.Loc}
+	}
+	return parts
+}
+
+// extendedDefinition returns definition extended by extension or nil when it is not defined
+func (agh *Actograph) extendedDefinition(kind string, name string) ast.Node {
+	switch kind {
+	case lexer.INPUT:
+		if definition, has := agh.inputObjectDefinitions[name]; has {
+			return definition
+		}
+	case lexer.INTERFACE:
+		if definition, has := agh.interfaceDefinitions[name]; has {
+			return definition
+		}
+	case lexer.ENUM:
+		if definition, has := agh.enumDefinitions[name]; has {
+			return definition
+		}
+	case lexer.UNION:
+		if definition, has := agh.unionDefinitions[name]; has {
+			return definition
+		}
+	case lexer.SCHEMA:
+		if agh.schema != nil {
+			return agh.schema
+		}
+	case lexer.SCALAR:
+		if definition, has := agh.declaredScalars[name]; has {
+			return ast.NewScalarDefinition(&ast.ScalarDefinition{
+				Name:       ast.NewName(&ast.Name{Value: name}),
+				Directives: definition.Directives,
+				Loc:        definition.Loc,
+			})
+		}
+	}
+	return nil
+}
+
+// extensionTarget accumulates members of extended definition to find conflicts. Directives may be repeated
+// like on fields, so they don't conflict
+type extensionTarget struct {
+	members map[string]bool
+}
+
+// extend adds members of extension to target and returns conflicts
+func (t *extensionTarget) extend(kind string, extension definitionParts) []error {
+	var errs []error
+	subject := fmt.Sprintf("%s '%s'", kind, extension.name)
+	if kind == lexer.SCHEMA {
+		subject = lexer.SCHEMA
+	}
+	for _, member := range extension.members {
+		if t.members[member] {
+			errs = append(errs, fmt.Errorf("%s extended at %s: %s '%s' is already defined",
+				subject, locationString(extension.loc), memberKinds[kind], member))
+		}
+		t.members[member] = true
+	}
+	return errs
+}
+
+func newExtensionTarget(definition definitionParts) *extensionTarget {
+	target := &extensionTarget{members: map[string]bool{}}
+	for _, member := range definition.members {
+		target.members[member] = true
+	}
+	return target
+}
+
+// mergeTypeExtensions checks extensions of objects and merges other extensions into definitions they extend.
+// Extensions are merged when there are no errors, so schema stays invalid until they are fixed
+func (agh *Actograph) mergeTypeExtensions() error {
+	var errs []error
+
+	objNames := make([]string, 0, len(agh.extensionDefinitions))
+	for name := range agh.extensionDefinitions {
+		objNames = append(objNames, name)
+	}
+	sort.Strings(objNames)
+	for _, name := range objNames {
+		objDefinition, has := agh.objectDefinitions[name]
+		for _, ext := range agh.extensionDefinitions[name] {
+			if !has {
+				errs = append(errs, fmt.Errorf("type '%s' extended at %s is not defined", name, locationString(ext.Loc)))
+			}
+		}
+		if !has {
+			continue
+		}
+		target := newExtensionTarget(partsOf(objDefinition))
+		for _, ext := range agh.extensionDefinitions[name] {
+			parts := partsOf(ext.Definition)
+			parts.loc = ext.Loc
+			errs = append(errs, target.extend(lexer.TYPE, parts)...)
+		}
+	}
+
+	targets := map[string]*extensionTarget{}
+	for _, ext := range agh.typeExtensions {
+		parts := partsOf(ext.node)
+		definition := agh.extendedDefinition(ext.kind, parts.name)
+		if definition == nil {
+			if ext.kind == lexer.SCHEMA {
+				errs = append(errs, fmt.Errorf("schema extended at %s is not defined", locationString(parts.loc)))
+			} else {
+				errs = append(errs, fmt.Errorf("%s '%s' extended at %s is not defined", ext.kind, parts.name, locationString(parts.loc)))
+			}
+			continue
+		}
+		if ext.kind == lexer.SCALAR || ext.kind == lexer.UNION || ext.kind == lexer.INTERFACE {
+			for _, dir := range parts.directives {
+				if !isHardcodedDirective(dir.Name.Value) {
+					errs = append(errs, fmt.Errorf("%s '%s' extended at %s: directives under %s are not implemented yet",
+						ext.kind, parts.name, locationString(parts.loc), ext.kind))
+				}
+			}
+		}
+
+		key := ext.kind + " " + parts.name
+		if _, has := targets[key]; !has {
+			targets[key] = newExtensionTarget(partsOf(definition))
+		}
+		errs = append(errs, targets[key].extend(ext.kind, parts)...)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, ext := range agh.typeExtensions {
+		agh.applyTypeExtension(ext)
+	}
+	agh.typeExtensions = nil
+	return nil
+}
+
+// applyTypeExtension adds members and directives of checked extension to extended definition
+func (agh *Actograph) applyTypeExtension(ext typeExtension) {
+	parts := partsOf(ext.node)
+	switch ext.kind {
+	case lexer.INPUT:
+		definition := agh.inputObjectDefinitions[parts.name]
+		definition.Directives = append(definition.Directives, parts.directives...)
+		if extension, ok := ext.node.(*ast.InputObjectDefinition); ok {
+			definition.Fields = append(definition.Fields, extension.Fields...)
+		}
+	case lexer.INTERFACE:
+		definition := agh.interfaceDefinitions[parts.name]
+		definition.Directives = append(definition.Directives, parts.directives...)
+		if extension, ok := ext.node.(*ast.InterfaceDefinition); ok {
+			definition.Fields = append(definition.Fields, extension.Fields...)
+		}
+	case lexer.ENUM:
+		definition := agh.enumDefinitions[parts.name]
+		definition.Directives = append(definition.Directives, parts.directives...)
+		if extension, ok := ext.node.(*ast.EnumDefinition); ok {
+			definition.Values = append(definition.Values, extension.Values...)
+		}
+	case lexer.UNION:
+		definition := agh.unionDefinitions[parts.name]
+		definition.Directives = append(definition.Directives, parts.directives...)
+		if extension, ok := ext.node.(*ast.UnionDefinition); ok {
+			definition.Types = append(definition.Types, extension.Types...)
+		}
+	case lexer.SCHEMA:
+		agh.schema.Directives = append(agh.schema.Directives, parts.directives...)
+		if extension, ok := ext.node.(*ast.SchemaDefinition); ok {
+			agh.schema.OperationTypes = append(agh.schema.OperationTypes, extension.OperationTypes...)
+		}
+	case lexer.SCALAR:
+		definition := agh.declaredScalars[parts.name]
+		definition.Directives = append(definition.Directives[:len(definition.Directives):len(definition.Directives)], parts.directives...)
+		agh.declaredScalars[parts.name] = definition
+	}
+}
@@ -1,30 +1,83 @@
 package actograph
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/graphql-go/graphql"
 
 	"github.com/actord/actograph/directive"
 )
 
-func (agh *Actograph) getFieldResolveFunc(directives []directive.Directive) graphql.FieldResolveFn {
+// getFieldResolveFunc makes resolver executing directives of field. Directives written by client in operation
+// are looked up only when schema variant has executable directives
+func (agh *Actograph) getFieldResolveFunc(directives []directive.Directive, hasExecutableDirectives bool) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (interface{}, error) {
 		currentFieldName := p.Info.FieldName
 		source := p.Source
 		args := p.Args
-		ctx := p.Context
-		var resolvedValue interface{}
+		// directives can take p.Info with directive.ResolveInfoFromContext (e.g. for directive.RequestedFields)
+		ctx := directive.ContextWithResolveInfo(p.Context, p.Info)
+		// take value of field from parent object as resolved value
+		resolvedValue := sourceFieldValue(source, currentFieldName)
 
-		// if object is a map - try to find key like field name as resolved value
-		if sourceMap, ok := source.(map[string]interface{}); ok {
-			if val, ok := sourceMap[currentFieldName]; ok {
-				resolvedValue = val
+		// directives written by client in operation are executed together with schema directives
+		fieldDirectives := directives
+		if hasExecutableDirectives {
+			executableDirectives, err := agh.fieldExecutableDirectives(ctx, p.Info)
+			if err != nil {
+				return nil, err
+			}
+			if len(executableDirectives) > 0 {
+				fieldDirectives = directive.SortByPhase(append(append([]directive.Directive{}, directives...), executableDirectives...))
 			}
 		}
 
 		// apply directives
-		var err error
-		resolvedValue, _, err = agh.executeDirectives(ctx, source, resolvedValue, args, directives)
-
+		// directive.ErrOmitField is returned as is, omittableResolveFunc resolves placeholder for it
+		resolvedValue, _, err := agh.executeDirectives(ctx, source, resolvedValue, args, fieldDirectives)
 		return resolvedValue, err
 	}
 }
+
+// sourceFieldValue takes value of field from parent object like graphql.DefaultResolveFn: by key of map
+// or by exported struct field named like field ignoring case or tagged `graphql:"name"` or `json:"name"`.
+// Methods of parent object are never called, bind type with BindType to resolve fields by methods
+func sourceFieldValue(source interface{}, fieldName string) interface{} {
+	if sourceMap, ok := source.(map[string]interface{}); ok {
+		return sourceMap[fieldName]
+	}
+	rv := reflect.ValueOf(source)
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		if value := rv.MapIndex(reflect.ValueOf(fieldName).Convert(rv.Type().Key())); value.IsValid() {
+			return value.Interface()
+		}
+		return nil
+	}
+
+	if structValue := reflect.Indirect(rv); structValue.Kind() == reflect.Struct {
+		for i := 0; i < structValue.NumField(); i++ {
+			field := structValue.Type().Field(i)
+			if field.IsExported() && isSourceField(field, fieldName) {
+				return structValue.Field(i).Interface()
+			}
+		}
+	}
+
+	return nil
+}
+
+func isSourceField(field reflect.StructField, fieldName string) bool {
+	if strings.EqualFold(field.Name, fieldName) {
+		return true
+	}
+	for _, tag := range []string{"graphql", "json"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name == fieldName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package actograph
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph/directive"
+)
+
+// RegisterGoScalar registers scalar named name for Go type T (not pointer). Scalar is represented
+// by string when *T implements encoding.TextMarshaler and encoding.TextUnmarshaler, otherwise by JSON value
+// when *T implements json.Marshaler and json.Unmarshaler. Arguments of scalar are passed to directives as T,
+// resolved value can be T or *T
+func RegisterGoScalar[T any](agh *Actograph, name string) error {
+	cfg, err := goScalarConfig[T](name)
+	if err != nil {
+		return fmt.Errorf("when registering scalar '%s': %w", name, err)
+	}
+	return agh.RegisterScalarE(cfg)
+}
+
+func goScalarConfig[T any](name string) (ScalarConfigE, error) {
+	var zero T
+	_, isTextMarshaler := any(&zero).(encoding.TextMarshaler)
+	_, isTextUnmarshaler := any(&zero).(encoding.TextUnmarshaler)
+	_, isJSONMarshaler := any(&zero).(json.Marshaler)
+	_, isJSONUnmarshaler := any(&zero).(json.Unmarshaler)
+
+	switch {
+	case isTextMarshaler && isTextUnmarshaler:
+		parseText := func(value interface{}) (interface{}, error) {
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected string, got %T", value)
+			}
+			var parsed T
+			if err := any(&parsed).(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+				return nil, err
+			}
+			return parsed, nil
+		}
+		return ScalarConfigE{
+			Name: name,
+			Serialize: func(value interface{}) (interface{}, error) {
+				p, err := goScalarPointer[T](value)
+				if err != nil {
+					return nil, err
+				}
+				text, err := any(p).(encoding.TextMarshaler).MarshalText()
+				if err != nil {
+					return nil, err
+				}
+				return string(text), nil
+			},
+			ParseValue: parseText,
+			ParseLiteral: func(valueAST ast.Value) (interface{}, error) {
+				if valueAST.GetKind() != "StringValue" {
+					return nil, fmt.Errorf("expected string literal, got %s", valueAST.GetKind())
+				}
+				return parseText(valueAST.GetValue())
+			},
+		}, nil
+	case isJSONMarshaler && isJSONUnmarshaler:
+		parseJSON := func(value interface{}) (interface{}, error) {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			var parsed T
+			if err := any(&parsed).(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+				return nil, err
+			}
+			return parsed, nil
+		}
+		return ScalarConfigE{
+			Name: name,
+			Serialize: func(value interface{}) (interface{}, error) {
+				p, err := goScalarPointer[T](value)
+				if err != nil {
+					return nil, err
+				}
+				data, err := any(p).(json.Marshaler).MarshalJSON()
+				if err != nil {
+					return nil, err
+				}
+				var serialized interface{}
+				if err := json.Unmarshal(data, &serialized); err != nil {
+					return nil, err
+				}
+				return serialized, nil
+			},
+			ParseValue: parseJSON,
+			ParseLiteral: func(valueAST ast.Value) (interface{}, error) {
+				return parseJSON(directive.LiteralValue(valueAST))
+			},
+		}, nil
+	}
+	return ScalarConfigE{}, fmt.Errorf("type %s implements neither encoding.TextMarshaler and encoding.TextUnmarshaler "+
+		"nor json.Marshaler and json.Unmarshaler", reflect.TypeOf(&zero).Elem())
+}
+
+// goScalarPointer returns pointer to resolved value of type T or *T
+func goScalarPointer[T any](value interface{}) (*T, error) {
+	switch value := value.(type) {
+	case T:
+		return &value, nil
+	case *T:
+		if value != nil {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("expected %s, got %T", reflect.TypeOf((*T)(nil)).Elem(), value)
+}
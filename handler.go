@@ -0,0 +1,100 @@
+package actograph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// HandlerConfig options for http.Handler made by Actograph.Handler
+type HandlerConfig struct {
+	// Audience picks variant of schema for request (see Actograph.SchemaFor). Full schema is used when nil
+	Audience func(r *http.Request) string
+
+	// RootObject returns root object for request. Optional
+	RootObject func(r *http.Request) map[string]interface{}
+}
+
+// requestBody is GraphQL over HTTP request: json body of POST request or query parameters of GET request
+type requestBody struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// Handler returns http.Handler that executes GraphQL requests sent as GET query parameters
+// or POST body (application/json or application/graphql) and writes Result as json
+func (agh *Actograph) Handler(cfg HandlerConfig) http.Handler {
+	return newHandler(cfg, agh.Do)
+}
+
+// newHandler makes http.Handler executing requests by do
+func newHandler(cfg HandlerConfig, do func(request RequestQuery) (*Result, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := parseRequestBody(r)
+		if err != nil {
+			writeResult(w, http.StatusBadRequest, &Result{Errors: gqlerrors.FormatErrors(err)})
+			return
+		}
+
+		request := RequestQuery{
+			RequestString:  body.Query,
+			VariableValues: body.Variables,
+			OperationName:  body.OperationName,
+			Context:        r.Context(),
+		}
+		if cfg.Audience != nil {
+			request.Audience = cfg.Audience(r)
+		}
+		if cfg.RootObject != nil {
+			request.RootObject = cfg.RootObject(r)
+		}
+
+		result, err := do(request)
+		if err != nil {
+			writeResult(w, http.StatusInternalServerError, &Result{Errors: gqlerrors.FormatErrors(err)})
+			return
+		}
+		writeResult(w, http.StatusOK, result)
+	})
+}
+
+func parseRequestBody(r *http.Request) (requestBody, error) {
+	var body requestBody
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		body.Query = query.Get("query")
+		body.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &body.Variables); err != nil {
+				return body, fmt.Errorf("when parsing variables: %w", err)
+			}
+		}
+	case http.MethodPost:
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/graphql") {
+			query, err := io.ReadAll(r.Body)
+			if err != nil {
+				return body, fmt.Errorf("when reading body: %w", err)
+			}
+			body.Query = string(query)
+			break
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return body, fmt.Errorf("when parsing body: %w", err)
+		}
+	default:
+		return body, fmt.Errorf("method %s is not allowed", r.Method)
+	}
+	return body, nil
+}
+
+func writeResult(w http.ResponseWriter, status int, result *Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,77 @@
+package actograph
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph/directive"
+)
+
+// noInheritDirectiveName is hardcoded directive for opting out field of type and schema directives:
+//
+//	directive @noInherit(directives: [String!]) on FIELD_DEFINITION
+//
+// when "directives" is omitted - none of inherited directives are applied to field
+const noInheritDirectiveName = "noInherit"
+
+// inheritedDirective is a directive placed on type or schema and prepended to directives chain of the fields
+type inheritedDirective struct {
+	name      string
+	directive directive.Directive
+}
+
+// makeTypeDirectives constructs directives placed on object type and its extensions
+func (agh *Actograph) makeTypeDirectives(objDefinition *ast.ObjectDefinition) []inheritedDirective {
+	usages := append([]*ast.Directive{}, objDefinition.Directives...)
+	for _, ext := range agh.extensionDefinitions[objDefinition.Name.Value] {
+		usages = append(usages, ext.Definition.Directives...)
+	}
+
+	inherited := make([]inheritedDirective, 0, len(usages))
+	for _, usage := range usages {
+		if isHardcodedDirective(usage.Name.Value) {
+			continue
+		}
+		dir, err := agh.ConstructDirective(usage, objDefinition)
+		if err != nil {
+			panic(fmt.Errorf("cant construct directive usage for @%s on type %s: %w", usage.Name.Value, objDefinition.Name.Value, err))
+		}
+		inherited = append(inherited, inheritedDirective{name: usage.Name.Value, directive: dir})
+	}
+	return inherited
+}
+
+// inheritDirectives returns inherited directives that should be prepended to field directives
+func (agh *Actograph) inheritDirectives(fieldDefinition *ast.FieldDefinition, inherited []inheritedDirective) []directive.Directive {
+	excluded := map[string]bool{}
+	for _, usage := range fieldDefinition.Directives {
+		name := usage.Name.Value
+		if name != noInheritDirectiveName {
+			// field directive overrides inherited one with the same name
+			excluded[name] = !isHardcodedDirective(name)
+			continue
+		}
+
+		var names *ast.ListValue
+		for _, arg := range usage.Arguments {
+			if arg.Name.Value == "directives" {
+				names, _ = arg.Value.(*ast.ListValue)
+			}
+		}
+		if names == nil {
+			return nil
+		}
+		for _, value := range names.Values {
+			excluded[value.GetValue().(string)] = true
+		}
+	}
+
+	directives := make([]directive.Directive, 0, len(inherited))
+	for _, dir := range inherited {
+		if !excluded[dir.name] {
+			directives = append(directives, dir.directive)
+		}
+	}
+	return directives
+}
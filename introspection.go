@@ -0,0 +1,175 @@
+package actograph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// internalDirectiveName is hardcoded directive that hides element from introspection, but element is still queryable:
+//
+//	directive @internal on OBJECT | FIELD_DEFINITION | ARGUMENT_DEFINITION | ENUM | ENUM_VALUE | INPUT_OBJECT
+//	    | INPUT_FIELD_DEFINITION | UNION | INTERFACE | SCALAR
+const internalDirectiveName = "internal"
+
+// SetIntrospectionPredicate allows __schema and __type introspection only for requests with context passing predicate.
+// Introspection is allowed for all requests by default, nil predicate restores default behaviour
+func (agh *Actograph) SetIntrospectionPredicate(predicate func(ctx context.Context) bool) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	agh.introspectionPredicate = predicate
+	return nil
+}
+
+// DisableIntrospection disallows __schema and __type introspection for all requests
+func (agh *Actograph) DisableIntrospection() error {
+	return agh.SetIntrospectionPredicate(func(ctx context.Context) bool {
+		return false
+	})
+}
+
+func isIntrospectionField(name string) bool {
+	return name == "__schema" || name == "__type"
+}
+
+// NoIntrospectionRule is validation rule that rejects __schema and __type fields
+func NoIntrospectionRule(context *graphql.ValidationContext) *graphql.ValidationRuleInstance {
+	return &graphql.ValidationRuleInstance{
+		VisitorOpts: &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.Field: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if node, ok := p.Node.(*ast.Field); ok && node.Name != nil && isIntrospectionField(node.Name.Value) {
+							context.ReportError(newValidationError(
+								fmt.Sprintf(`Introspection is not allowed, cannot query field "%s".`, node.Name.Value),
+								node,
+							))
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+			},
+		},
+	}
+}
+
+// hasInternalElements reports is there any element marked by @internal
+func (agh *Actograph) hasInternalElements() bool {
+	hasInternal := false
+	agh.walkDirectives(func(directives []*ast.Directive) {
+		hasInternal = hasInternal || hasDirective(directives, internalDirectiveName)
+	})
+	return hasInternal
+}
+
+// validationRules returns rules for validating request with ctx
+func (agh *Actograph) validationRules(ctx context.Context) []graphql.ValidationRuleFn {
+	rules := agh.specifiedRules()
+	if agh.introspectionPredicate != nil && !agh.introspectionPredicate(ctx) {
+		rules = append(rules[:len(rules):len(rules)], NoIntrospectionRule)
+	}
+	return rules
+}
+
+// splitIntrospection splits operation to execute in document by root fields into document with introspection fields
+// and document with other fields, so introspection can be executed on schema without @internal elements.
+// Fragment spreads of root selection set are inlined. Document is nil when operation has no such fields
+func splitIntrospection(doc *ast.Document, operationName string) (introspection *ast.Document, other *ast.Document) {
+	operation := findOperation(doc, operationName)
+	if operation == nil {
+		return nil, doc
+	}
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range doc.Definitions {
+		if definition, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[definition.Name.Value] = definition
+		}
+	}
+
+	withSelections := func(selectionSet *ast.SelectionSet) *ast.Document {
+		if selectionSet == nil {
+			return nil
+		}
+		split := *operation
+		split.SelectionSet = selectionSet
+		definitions := []ast.Node{&split}
+		for _, definition := range doc.Definitions {
+			if definition, ok := definition.(*ast.FragmentDefinition); ok {
+				definitions = append(definitions, definition)
+			}
+		}
+		return ast.NewDocument(&ast.Document{Loc: doc.Loc, Definitions: definitions})
+	}
+	return withSelections(rootSelections(operation.SelectionSet, fragments, true)),
+		withSelections(rootSelections(operation.SelectionSet, fragments, false))
+}
+
+// rootSelections returns selections of introspection fields or of other fields (including __typename),
+// nil when there are no such fields
+func rootSelections(
+	selectionSet *ast.SelectionSet,
+	fragments map[string]*ast.FragmentDefinition,
+	introspection bool,
+) *ast.SelectionSet {
+	if selectionSet == nil {
+		return nil
+	}
+	var selections []ast.Selection
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if isIntrospectionField(selection.Name.Value) == introspection {
+				selections = append(selections, selection)
+			}
+		case *ast.InlineFragment:
+			if fragmentSelections := rootSelections(selection.SelectionSet, fragments, introspection); fragmentSelections != nil {
+				inline := *selection
+				inline.SelectionSet = fragmentSelections
+				selections = append(selections, &inline)
+			}
+		case *ast.FragmentSpread:
+			definition := fragments[selection.Name.Value]
+			if definition == nil {
+				continue
+			}
+			if fragmentSelections := rootSelections(definition.SelectionSet, fragments, introspection); fragmentSelections != nil {
+				selections = append(selections, ast.NewInlineFragment(&ast.InlineFragment{
+					Loc:           selection.Loc,
+					TypeCondition: definition.TypeCondition,
+					Directives:    selection.Directives,
+					SelectionSet:  fragmentSelections,
+				}))
+			}
+		}
+	}
+	if len(selections) == 0 {
+		return nil
+	}
+	return ast.NewSelectionSet(&ast.SelectionSet{Loc: selectionSet.Loc, Selections: selections})
+}
+
+// mergeResults adds data and errors of introspection fields to result of other fields
+func mergeResults(result *graphql.Result, introspection *graphql.Result) *graphql.Result {
+	result.Errors = append(result.Errors, introspection.Errors...)
+	data, ok := result.Data.(map[string]interface{})
+	introspectionData, introspectionOk := introspection.Data.(map[string]interface{})
+	if !ok || !introspectionOk {
+		// null propagated to the root
+		result.Data = nil
+		return result
+	}
+	for key, value := range introspectionData {
+		data[key] = value
+	}
+	return result
+}
+
+func newValidationError(message string, node ast.Node) *gqlerrors.Error {
+	return gqlerrors.NewError(message, []ast.Node{node}, "", nil, []int{}, nil)
+}
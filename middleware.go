@@ -0,0 +1,60 @@
+package actograph
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Handler executes request with ctx. The innermost Handler executes schema directives and operation,
+// middlewares can replace ctx and request or rewrite result
+type Handler func(ctx context.Context, request RequestQuery) *Result
+
+// Middleware wraps next Handler, e.g. for auth, logging, panics recovery and timing
+type Middleware func(next Handler) Handler
+
+// Use registers middlewares wrapping every request executed by Do, Handler and Reloader.
+// The first registered middleware is the outermost
+func (agh *Actograph) Use(middlewares ...Middleware) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	agh.middlewares = append(agh.middlewares, middlewares...)
+	return nil
+}
+
+// chainMiddlewares wraps handler by middlewares, the first middleware is the outermost
+func chainMiddlewares(middlewares []Middleware, handler Handler) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// FieldResolver resolves field, see UseField
+type FieldResolver func(p graphql.ResolveParams) (interface{}, error)
+
+// FieldMiddleware wraps next FieldResolver, e.g. for tracing, metrics and panics recovery
+type FieldMiddleware func(next FieldResolver) FieldResolver
+
+// UseField registers middlewares wrapping resolvers of fields. Trivial fields without directives (including
+// directives inherited from type or schema), registered resolvers, bound methods, @node and @connection only take
+// value from parent object, they skip middlewares to keep overhead low unless client writes directives on them.
+// Omitted fields come back to middlewares as directive.ErrOmitField, which must be returned as is to remove field
+// from response, and fields of omitted objects skip middlewares.
+// The first registered middleware is the outermost
+func (agh *Actograph) UseField(middlewares ...FieldMiddleware) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	agh.fieldMiddlewares = append(agh.fieldMiddlewares, middlewares...)
+	return nil
+}
+
+// chainFieldMiddlewares wraps resolver by middlewares, the first middleware is the outermost
+func chainFieldMiddlewares(middlewares []FieldMiddleware, resolver FieldResolver) FieldResolver {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		resolver = middlewares[i](resolver)
+	}
+	return resolver
+}
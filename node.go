@@ -0,0 +1,257 @@
+package actograph
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph/directive"
+)
+
+// nodeDirectiveName is hardcoded directive that makes object refetchable by global ID (relay object identification):
+//
+//	directive @node on OBJECT
+//
+// type marked by @node implements interface `Node { id: ID! }` and its `id` field resolves global ID made by
+// directive.EncodeGlobalID from resolved id. Fields `node(id: ID!): Node` and `nodes(ids: [ID!]!): [Node]!`
+// are added to query type, they fetch objects by functions registered with RegisterNodeFetcher.
+// Interface Node is defined automatically when it is not defined in schema
+const nodeDirectiveName = "node"
+
+const nodeInterfaceName = "Node"
+
+// NodeFetcher fetches object of type marked by @node by its id (not global ID). nil means object not found.
+// Fetched map[string]interface{} gets "__typename" of type, other values should implement Typenamer
+type NodeFetcher func(ctx context.Context, id string) (interface{}, error)
+
+// RegisterNodeFetcher registers function fetching objects of type marked by @node, every such type
+// should have registered fetcher
+func (agh *Actograph) RegisterNodeFetcher(typeName string, fetch NodeFetcher) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	if _, has := agh.nodeFetchers[typeName]; has {
+		return fmt.Errorf("node fetcher for type '%s' already registered", typeName)
+	}
+	if !agh.isNodeType(typeName) {
+		return fmt.Errorf("type '%s' is not marked by @%s", typeName, nodeDirectiveName)
+	}
+	agh.nodeFetchers[typeName] = fetch
+	return nil
+}
+
+// isNodeType reports is object type or its extension marked by @node
+func (agh *Actograph) isNodeType(typeName string) bool {
+	objDefinition, has := agh.objectDefinitions[typeName]
+	if !has {
+		return false
+	}
+	if hasDirective(objDefinition.Directives, nodeDirectiveName) {
+		return true
+	}
+	for _, ext := range agh.extensionDefinitions[typeName] {
+		if hasDirective(ext.Definition.Directives, nodeDirectiveName) {
+			return true
+		}
+	}
+	return false
+}
+
+// makeNodes adds Node interface to types marked by @node and node and nodes fields to query type
+func (agh *Actograph) makeNodes() error {
+	hasNodes := false
+	for objName, objDefinition := range agh.objectDefinitions {
+		if !agh.isNodeType(objName) {
+			continue
+		}
+		hasNodes = true
+
+		if _, has := agh.nodeFetchers[objName]; !has {
+			return fmt.Errorf("node fetcher for type '%s' is not registered", objName)
+		}
+
+		var idField *ast.FieldDefinition
+		for _, fieldDefinition := range agh.objectFields(objName, objDefinition) {
+			if fieldDefinition.Name.Value == "id" {
+				idField = fieldDefinition
+			}
+		}
+		if idField == nil || namedTypeName(idField.Type) != "ID" || idField.Type.GetKind() != "NonNull" {
+			return fmt.Errorf("type '%s' marked by @%s should have field 'id: ID!'", objName, nodeDirectiveName)
+		}
+		if _, has := agh.nodeResolvers[idField]; !has {
+			agh.nodeResolvers[idField] = &globalIDResolver{typeName: objName}
+		}
+
+		if !hasInterface(agh.objectInterfaces(objName, objDefinition), nodeInterfaceName) {
+			objDefinition.Interfaces = append(objDefinition.Interfaces, named(nodeInterfaceName))
+		}
+	}
+	if !hasNodes {
+		return nil
+	}
+
+	if _, has := agh.interfaceDefinitions[nodeInterfaceName]; !has {
+		idField := syntheticField("id", nonNullNamed("ID"))
+		idField.Description = ast.NewStringValue(&ast.StringValue{Value: "The global ID of the object."})
+		agh.interfaceDefinitions[nodeInterfaceName] = ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+			Name:        ast.NewName(&ast.Name{Value: nodeInterfaceName}),
+			Description: ast.NewStringValue(&ast.StringValue{Value: "An object with a global ID."}),
+			Fields:      []*ast.FieldDefinition{idField},
+		})
+	}
+
+	queryTypename := agh.operationTypename("query")
+	queryDefinition, has := agh.objectDefinitions[queryTypename]
+	if !has {
+		return fmt.Errorf("not found object declared as schema.query: %s", queryTypename)
+	}
+	fields := agh.objectFields(queryTypename, queryDefinition)
+	if !hasField(fields, "node") {
+		nodeField := syntheticField("node", named(nodeInterfaceName))
+		nodeField.Description = ast.NewStringValue(&ast.StringValue{Value: "Fetches an object given its global ID."})
+		nodeField.Arguments = []*ast.InputValueDefinition{ast.NewInputValueDefinition(&ast.InputValueDefinition{
+			Name: ast.NewName(&ast.Name{Value: "id"}),
+			Type: nonNullNamed("ID"),
+		})}
+		queryDefinition.Fields = append(queryDefinition.Fields, nodeField)
+		agh.nodeResolvers[nodeField] = &nodeResolver{agh: agh}
+	}
+	if !hasField(fields, "nodes") {
+		nodesField := syntheticField("nodes", ast.NewNonNull(&ast.NonNull{Type: ast.NewList(&ast.List{Type: named(nodeInterfaceName)})}))
+		nodesField.Description = ast.NewStringValue(&ast.StringValue{Value: "Fetches objects given their global IDs."})
+		nodesField.Arguments = []*ast.InputValueDefinition{ast.NewInputValueDefinition(&ast.InputValueDefinition{
+			Name: ast.NewName(&ast.Name{Value: "ids"}),
+			Type: ast.NewNonNull(&ast.NonNull{Type: ast.NewList(&ast.List{Type: nonNullNamed("ID")})}),
+		})}
+		queryDefinition.Fields = append(queryDefinition.Fields, nodesField)
+		agh.nodeResolvers[nodesField] = &nodeResolver{agh: agh, list: true}
+	}
+	return nil
+}
+
+// operationTypename returns name of type declared in schema for operation ("query" or "mutation")
+func (agh *Actograph) operationTypename(operation string) string {
+	for _, ot := range agh.schema.OperationTypes {
+		if ot.Operation == operation {
+			return ot.Type.Name.Value
+		}
+	}
+	return ""
+}
+
+func hasInterface(interfaces []string, name string) bool {
+	for _, iface := range interfaces {
+		if iface == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasField(fields []*ast.FieldDefinition, name string) bool {
+	for _, field := range fields {
+		if field.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchNode fetches object by global ID, nil is returned for unknown types
+func (agh *Actograph) fetchNode(ctx context.Context, globalID string) (interface{}, error) {
+	typeName, id, err := directive.DecodeGlobalID(globalID)
+	if err != nil {
+		return nil, err
+	}
+	fetch, has := agh.nodeFetchers[typeName]
+	if !has {
+		return nil, nil
+	}
+
+	node, err := fetch(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("when fetching %s: %w", typeName, err)
+	}
+	if nodeMap, ok := node.(map[string]interface{}); ok {
+		if _, has := nodeMap["__typename"]; !has {
+			typed := make(map[string]interface{}, len(nodeMap)+1)
+			for key, value := range nodeMap {
+				typed[key] = value
+			}
+			typed["__typename"] = typeName
+			node = typed
+		}
+	}
+	return node, nil
+}
+
+// nodeResolver resolves generated node and nodes query fields
+type nodeResolver struct {
+	agh  *Actograph
+	list bool
+}
+
+func (n *nodeResolver) Phase() directive.Phase {
+	return directive.PhaseResolve
+}
+
+func (n *nodeResolver) Execute(
+	ctx context.Context,
+	_ interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	_ interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	if !n.list {
+		globalID, _ := fieldArgs["id"].(string)
+		node, err := n.agh.fetchNode(ctx, globalID)
+		return node, ctx, err
+	}
+
+	ids, _ := fieldArgs["ids"].([]interface{})
+	nodes := make([]interface{}, len(ids))
+	for i, id := range ids {
+		globalID, _ := id.(string)
+		node, err := n.agh.fetchNode(ctx, globalID)
+		if err != nil {
+			return nil, ctx, err
+		}
+		nodes[i] = node
+	}
+	return nodes, ctx, nil
+}
+
+func (n *nodeResolver) Define(_ string, _ interface{}) error {
+	return nil
+}
+
+// globalIDResolver is the last directive of id field of type marked by @node, it makes global ID from resolved id
+type globalIDResolver struct {
+	typeName string
+}
+
+func (g *globalIDResolver) Phase() directive.Phase {
+	return directive.PhaseAfterResolve
+}
+
+func (g *globalIDResolver) Priority() int {
+	return math.MinInt
+}
+
+func (g *globalIDResolver) Execute(
+	ctx context.Context,
+	_ interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	resolvedValue interface{}, // previously resolved value
+	_ map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	if resolvedValue == nil {
+		return nil, ctx, nil
+	}
+	return directive.EncodeGlobalID(g.typeName, fmt.Sprint(resolvedValue)), ctx, nil
+}
+
+func (g *globalIDResolver) Define(_ string, _ interface{}) error {
+	return nil
+}
@@ -0,0 +1,103 @@
+package actograph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/actord/actograph/directive"
+)
+
+// omittedObject is resolved for omitted fields of object type, fields of omitted object are omitted too
+type omittedObject struct{}
+
+// omittableResolveFunc records fields omitted with directive.ErrOmitField and resolves placeholders for them.
+// Resolvers, directives and field middlewares of fields of omitted objects are not executed,
+// so only graphql completion and scalars serialization meet placeholders
+func omittableResolveFunc(resolve FieldResolver) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if _, omitted := p.Source.(omittedObject); omitted {
+			// parent field is omitted, so this value will be removed from response too
+			return omittedPlaceholder(p.Info.ReturnType), nil
+		}
+		resolvedValue, err := resolve(p)
+		if err != directive.ErrOmitField {
+			return resolvedValue, err
+		}
+		if state := requestStateFromContext(p.Context); state != nil {
+			state.omit(p.Info.Path.AsArray())
+			return omittedPlaceholder(p.Info.ReturnType), nil
+		}
+		return nil, nil
+	}
+}
+
+// omittedPlaceholder returns value that can be completed by graphql for the type of omitted field.
+// Nullable fields just resolve null, non-null fields need some non-null value to avoid null propagation to the parent,
+// anyway it will be removed from response by omitFields. Non-null scalars get the first of 0, "" and false they
+// serialize, scalars serializing none of them resolve null
+func omittedPlaceholder(ttype graphql.Type) interface{} {
+	nonNull, ok := ttype.(*graphql.NonNull)
+	if !ok {
+		return nil
+	}
+
+	switch ttype := nonNull.OfType.(type) {
+	case *graphql.List:
+		return []interface{}{}
+	case *graphql.Enum:
+		return ttype.Values()[0].Value
+	case *graphql.Scalar:
+		for _, candidate := range []interface{}{0, "", false} {
+			if serializable(ttype, candidate) {
+				return candidate
+			}
+		}
+		return nil
+	default:
+		// objects and abstract types
+		return omittedObject{}
+	}
+}
+
+func serializable(scalar *graphql.Scalar, value interface{}) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	return scalar.Serialize(value) != nil
+}
+
+// omitFields removes fields by paths from response data
+func omitFields(data interface{}, paths [][]interface{}) interface{} {
+	for _, path := range paths {
+		omitField(data, path)
+	}
+	return data
+}
+
+func omitField(data interface{}, path []interface{}) {
+	if len(path) == 0 {
+		return
+	}
+	for _, key := range path[:len(path)-1] {
+		switch key := key.(type) {
+		case string:
+			obj, ok := data.(map[string]interface{})
+			if !ok {
+				return
+			}
+			data = obj[key]
+		case int:
+			list, ok := data.([]interface{})
+			if !ok || key >= len(list) {
+				return
+			}
+			data = list[key]
+		}
+	}
+	if obj, ok := data.(map[string]interface{}); ok {
+		if key, ok := path[len(path)-1].(string); ok {
+			delete(obj, key)
+		}
+	}
+}
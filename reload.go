@@ -0,0 +1,209 @@
+package actograph
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/actord/actograph/directive"
+)
+
+// Reloader serves actograph made by NewActographFiles or NewActographFS and replaces it when schema files change.
+// Actograph is rebuilt from the same files with the same registrations (directives, scalars, resolvers, bindings,
+// middlewares, builders and settings), and replaces current one only when schema is valid. Requests being
+// executed finish on the schema they started with
+type Reloader struct {
+	current atomic.Pointer[Executable]
+
+	// reloadMu serializes reloads
+	reloadMu    sync.Mutex
+	fingerprint [sha256.Size]byte
+}
+
+// NewReloader validates actograph and starts serving it
+func NewReloader(agh *Actograph) (*Reloader, error) {
+	if agh.readSources == nil {
+		return nil, errors.New("actograph is not made from schema files, it can't be reloaded")
+	}
+	exe, err := agh.Build()
+	if err != nil {
+		return nil, err
+	}
+	r := &Reloader{fingerprint: agh.sourcesFingerprint}
+	r.current.Store(exe)
+	return r, nil
+}
+
+// Executable returns currently served executable
+func (r *Reloader) Executable() *Executable {
+	return r.current.Load()
+}
+
+// Do executes request by currently served actograph
+func (r *Reloader) Do(request RequestQuery) (*Result, error) {
+	return r.current.Load().Do(request)
+}
+
+// Handler returns http.Handler executing requests by currently served actograph, see Actograph.Handler
+func (r *Reloader) Handler(cfg HandlerConfig) http.Handler {
+	return newHandler(cfg, r.Do)
+}
+
+// Reload rebuilds actograph when schema files changed since the last reload. When rebuilt schema is invalid,
+// error is returned (only once for the same files) and current actograph is still served
+func (r *Reloader) Reload() (reloaded bool, err error) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	current := r.current.Load().agh
+	files, err := current.readSources()
+	if err != nil {
+		return false, fmt.Errorf("when reading schema files: %w", err)
+	}
+	fingerprint := fingerprintOf(files)
+	if fingerprint == r.fingerprint {
+		return false, nil
+	}
+	r.fingerprint = fingerprint
+
+	next, err := current.rebuild(files)
+	if err != nil {
+		return false, fmt.Errorf("when reloading schema: %w", err)
+	}
+	r.current.Store(next)
+	return true, nil
+}
+
+// Watch polls schema files every interval and reloads actograph when they change, until ctx is done.
+// report is called with nil after actograph was replaced and with error when files can't be reloaded, it may be nil
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration, report func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		reloaded, err := r.Reload()
+		if report != nil && (reloaded || err != nil) {
+			report(err)
+		}
+	}
+}
+
+// registrations are made in Go code rather than parsed from schema files. Reloader copies all of them
+// to rebuilt actograph at once, so every registration is kept on reload
+type registrations struct {
+	// directives registered by RegisterDirective mapped by name
+	directiveDeclarations map[string]directive.Definition
+	// fetch functions of types marked by @node, see RegisterNodeFetcher
+	nodeFetchers map[string]NodeFetcher
+	// functions registered by RegisterResolver mapped by field coordinate
+	resolvers map[string]interface{}
+	// values bound by BindType mapped by type name, their methods resolve fields of type
+	typeBindings map[string]interface{}
+	// middlewares registered by Use, the first one is the outermost
+	middlewares []Middleware
+	// middlewares registered by UseField, the first one is the outermost
+	fieldMiddlewares []FieldMiddleware
+	// configs of scalars registered by RegisterScalarE, used to report invalid values
+	scalarConfigs map[string]ScalarConfigE
+	// configs of scalars registered by RegisterScalar, graphql reports their invalid values as before
+	legacyScalarConfigs map[string]ScalarConfig
+	// Go values of enum values by enum name, see BindEnum
+	enumBindings map[string]map[string]interface{}
+	// builtin scalars (String, Int, etc.) can be replaced by RegisterScalar, see AllowBuiltinScalarOverride
+	allowBuiltinScalarOverride bool
+	// introspectionPredicate allows introspection for request, introspection is allowed for all requests when nil
+	introspectionPredicate func(ctx context.Context) bool
+	// builders of definitions added in Go code, see Add
+	builders []DefinitionBuilder
+}
+
+func newRegistrations() registrations {
+	return registrations{
+		directiveDeclarations: map[string]directive.Definition{},
+		nodeFetchers:          map[string]NodeFetcher{},
+		resolvers:             map[string]interface{}{},
+		typeBindings:          map[string]interface{}{},
+		scalarConfigs:         map[string]ScalarConfigE{},
+		legacyScalarConfigs:   map[string]ScalarConfig{},
+		enumBindings:          map[string]map[string]interface{}{},
+	}
+}
+
+// clone copies registrations, registering in the copy doesn't change the original
+func (r registrations) clone() registrations {
+	cloned := r
+	cloned.directiveDeclarations = copyMap(r.directiveDeclarations)
+	cloned.nodeFetchers = copyMap(r.nodeFetchers)
+	cloned.resolvers = copyMap(r.resolvers)
+	cloned.typeBindings = copyMap(r.typeBindings)
+	cloned.scalarConfigs = copyMap(r.scalarConfigs)
+	cloned.legacyScalarConfigs = copyMap(r.legacyScalarConfigs)
+	cloned.enumBindings = copyMap(r.enumBindings)
+	cloned.middlewares = r.middlewares[:len(r.middlewares):len(r.middlewares)]
+	cloned.fieldMiddlewares = r.fieldMiddlewares[:len(r.fieldMiddlewares):len(r.fieldMiddlewares)]
+	cloned.builders = r.builders[:len(r.builders):len(r.builders)]
+	return cloned
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	copied := make(map[K]V, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
+
+// rebuild builds actograph from files with registrations of agh. Directives, node fetchers and bindings of enums
+// are kept even when they are not used by schema anymore, resolvers and bindings of removed fields fail the build
+// like for new actograph
+func (agh *Actograph) rebuild(files []schemaFile) (exe *Executable, err error) {
+	defer func() {
+		// some invalid schemas panic while parsing, it shouldn't stop serving current schema
+		if recovered := recover(); recovered != nil {
+			exe, err = nil, fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	next := NewActograph()
+	if err := next.parseFiles(files); err != nil {
+		return nil, err
+	}
+	next.readSources = agh.readSources
+	next.sourcesFingerprint = fingerprintOf(files)
+	next.registrations = agh.registrations.clone()
+
+	// registrations that change definitions and types are applied to parsed definitions again
+	builders := next.builders
+	next.builders = nil
+	if err := next.Add(builders...); err != nil {
+		return nil, err
+	}
+	for name, cfg := range next.scalarConfigs {
+		next.scalars[name] = newScalar(cfg)
+	}
+	for name, cfg := range next.legacyScalarConfigs {
+		next.scalars[name] = newLegacyScalar(cfg)
+	}
+	enumBindings := next.enumBindings
+	next.enumBindings = map[string]map[string]interface{}{}
+	for enumName, binding := range enumBindings {
+		if _, has := next.enumDefinitions[enumName]; !has {
+			next.enumBindings[enumName] = binding
+			continue
+		}
+		if err := next.bindEnum(enumName, binding); err != nil {
+			return nil, err
+		}
+	}
+
+	return next.Build()
+}
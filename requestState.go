@@ -0,0 +1,34 @@
+package actograph
+
+import (
+	"context"
+	"sync"
+)
+
+type requestStateContextKey struct{}
+
+// requestState keeps state of single request, it's created in Do for every request
+type requestState struct {
+	directives requestDirectives
+
+	mx sync.Mutex
+	// response paths of fields omitted with directive.ErrOmitField
+	omitted [][]interface{}
+}
+
+func contextWithRequestState(ctx context.Context) (context.Context, *requestState) {
+	state := &requestState{}
+	return context.WithValue(ctx, requestStateContextKey{}, state), state
+}
+
+// requestStateFromContext returns state of current request or nil when field is resolved outside of Do
+func requestStateFromContext(ctx context.Context) *requestState {
+	state, _ := ctx.Value(requestStateContextKey{}).(*requestState)
+	return state
+}
+
+func (state *requestState) omit(path []interface{}) {
+	state.mx.Lock()
+	defer state.mx.Unlock()
+	state.omitted = append(state.omitted, path)
+}
@@ -0,0 +1,363 @@
+package actograph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph/directive"
+)
+
+// ResolverFunc resolves field like directive of directive.PhaseResolve. Source is parent object and args are
+// arguments of field with default values
+type ResolverFunc func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error)
+
+var (
+	resolverFuncType = reflect.TypeOf(ResolverFunc(nil))
+	contextType      = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType        = reflect.TypeOf((*error)(nil)).Elem()
+	argumentsType    = reflect.TypeOf(map[string]interface{}(nil))
+)
+
+// RegisterResolver registers function resolving field by its coordinate "Type.field". Resolver is either
+// ResolverFunc or function with typed parameters:
+//
+//	func([ctx context.Context,] [source S,] [args A]) (R[, error])
+//
+// When function takes one parameter besides context, it is arguments for fields with arguments and source for fields
+// without arguments. Arguments are decoded into A (struct, pointer to struct or map[string]interface{}), struct
+// fields are matched to arguments by `graphql:"name"` tag or by case-insensitive name.
+//
+// Resolver is executed before directives of directive.PhaseResolve, so directives still run around it.
+// Schema is not built when type or field of coordinate is not defined
+func (agh *Actograph) RegisterResolver(coordinate string, resolver interface{}) error {
+	if err := agh.checkNotBuilt(); err != nil {
+		return err
+	}
+	typeName, fieldName, ok := strings.Cut(coordinate, ".")
+	if !ok || typeName == "" || fieldName == "" {
+		return fmt.Errorf("invalid field coordinate '%s', expected 'Type.field'", coordinate)
+	}
+	if _, has := agh.resolvers[coordinate]; has {
+		return fmt.Errorf("resolver for '%s' already registered", coordinate)
+	}
+	if err := checkResolverType(reflect.TypeOf(resolver)); err != nil {
+		return fmt.Errorf("resolver for '%s': %w", coordinate, err)
+	}
+	agh.resolvers[coordinate] = resolver
+	return nil
+}
+
+// makeFieldResolvers finds fields of registered resolvers and adapts resolvers to their arguments
+func (agh *Actograph) makeFieldResolvers() error {
+	agh.fieldResolvers = map[*ast.FieldDefinition]directive.Directive{}
+
+	coordinates := make([]string, 0, len(agh.resolvers))
+	for coordinate := range agh.resolvers {
+		coordinates = append(coordinates, coordinate)
+	}
+	sort.Strings(coordinates)
+
+	var errs []error
+	for _, coordinate := range coordinates {
+		typeName, fieldName, _ := strings.Cut(coordinate, ".")
+		objDefinition, has := agh.objectDefinitions[typeName]
+		if !has {
+			errs = append(errs, fmt.Errorf("resolver registered for '%s', but type '%s' is not defined", coordinate, typeName))
+			continue
+		}
+		var fieldDefinition *ast.FieldDefinition
+		for _, field := range agh.objectFields(typeName, objDefinition) {
+			if field.Name.Value == fieldName {
+				fieldDefinition = field
+			}
+		}
+		if fieldDefinition == nil {
+			errs = append(errs, fmt.Errorf("resolver registered for '%s', but type '%s' has no field '%s'", coordinate, typeName, fieldName))
+			continue
+		}
+
+		resolve, err := adaptResolver(reflect.ValueOf(agh.resolvers[coordinate]), fieldDefinition)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolver for '%s': %w", coordinate, err))
+			continue
+		}
+		agh.fieldResolvers[fieldDefinition] = &fieldResolver{resolve: resolve}
+	}
+	errs = append(errs, agh.makeBoundResolvers()...)
+	return errors.Join(errs...)
+}
+
+// checkResolverType checks parts of resolver signature that don't depend on field
+func checkResolverType(fnType reflect.Type) error {
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return fmt.Errorf("expected function, got %v", fnType)
+	}
+	if fnType.ConvertibleTo(resolverFuncType) {
+		return nil
+	}
+	if fnType.IsVariadic() {
+		return fmt.Errorf("variadic function %s is not supported", fnType)
+	}
+	params := fnType.NumIn()
+	if params > 0 && fnType.In(0) == contextType {
+		params--
+	}
+	if params > 2 {
+		return fmt.Errorf("function %s takes too many parameters", fnType)
+	}
+	switch {
+	case fnType.NumOut() == 1 && fnType.Out(0) != errorType:
+	case fnType.NumOut() == 2 && fnType.Out(1) == errorType:
+	default:
+		return fmt.Errorf("function %s should return value or value and error", fnType)
+	}
+	return nil
+}
+
+// adaptResolver makes ResolverFunc calling fn, parameters of fn are chosen by arguments of field
+func adaptResolver(fn reflect.Value, field *ast.FieldDefinition) (ResolverFunc, error) {
+	fnType := fn.Type()
+	if err := checkResolverType(fnType); err != nil {
+		return nil, err
+	}
+	if fnType.ConvertibleTo(resolverFuncType) {
+		return fn.Convert(resolverFuncType).Interface().(ResolverFunc), nil
+	}
+
+	takesContext := fnType.NumIn() > 0 && fnType.In(0) == contextType
+	params := make([]reflect.Type, 0, 2)
+	for i := 0; i < fnType.NumIn(); i++ {
+		if i == 0 && takesContext {
+			continue
+		}
+		params = append(params, fnType.In(i))
+	}
+	var sourceType, argsType reflect.Type
+	switch {
+	case len(params) == 2:
+		sourceType, argsType = params[0], params[1]
+	case len(params) == 1 && len(field.Arguments) > 0:
+		argsType = params[0]
+	case len(params) == 1:
+		sourceType = params[0]
+	}
+	if argsType != nil && argsType != argumentsType {
+		if err := checkArgumentsType(argsType, field); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+		in := make([]reflect.Value, 0, fnType.NumIn())
+		if takesContext {
+			in = append(in, reflect.ValueOf(&ctx).Elem())
+		}
+		if sourceType != nil {
+			sourceValue, err := sourceOf(source, sourceType)
+			if err != nil {
+				return nil, err
+			}
+			in = append(in, sourceValue)
+		}
+		if argsType != nil {
+			argsValue, err := decodeValue(args, argsType)
+			if err != nil {
+				return nil, fmt.Errorf("when decoding arguments: %w", err)
+			}
+			in = append(in, argsValue)
+		}
+
+		out := fn.Call(in)
+		if len(out) == 2 && !out[1].IsNil() {
+			return nil, out[1].Interface().(error)
+		}
+		return out[0].Interface(), nil
+	}, nil
+}
+
+// checkArgumentsType checks that every field of arguments struct matches argument of field
+func checkArgumentsType(argsType reflect.Type, field *ast.FieldDefinition) error {
+	structType := indirectType(argsType)
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("arguments should be decoded into struct or map[string]interface{}, got %s", argsType)
+	}
+	arguments := make(map[string]interface{}, len(field.Arguments))
+	for _, argument := range field.Arguments {
+		arguments[argument.Name.Value] = nil
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		if _, has := lookupField(arguments, structField); !has {
+			return fmt.Errorf("field %s of %s doesn't match any argument of field '%s'", structField.Name, argsType, field.Name.Value)
+		}
+	}
+	return nil
+}
+
+// fieldResolver executes registered resolver before directives of directive.PhaseResolve
+type fieldResolver struct {
+	resolve ResolverFunc
+}
+
+func (r *fieldResolver) Phase() directive.Phase {
+	return directive.PhaseResolve
+}
+
+func (r *fieldResolver) Priority() int {
+	return math.MaxInt
+}
+
+func (r *fieldResolver) Execute(
+	ctx context.Context,
+	source interface{}, // parent object. Not map[string]interface{} for scalars resolvers or nil
+	_ interface{}, // previously resolved value
+	fieldArgs map[string]interface{}, // field arguments value
+) (interface{}, context.Context, error) { // resolved value with updated context or error
+	value, err := r.resolve(ctx, source, fieldArgs)
+	return value, ctx, err
+}
+
+func (r *fieldResolver) Define(_ string, _ interface{}) error {
+	return nil
+}
+
+func sourceOf(source interface{}, sourceType reflect.Type) (reflect.Value, error) {
+	if source == nil {
+		return reflect.Zero(sourceType), nil
+	}
+	rv := reflect.ValueOf(source)
+	if !rv.Type().AssignableTo(sourceType) {
+		return reflect.Value{}, fmt.Errorf("expected source of type %s, got %T", sourceType, source)
+	}
+	return rv, nil
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// decodeValue converts value of arguments (maps, slices and scalars) to target type
+func decodeValue(value interface{}, target reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(target), nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(target) {
+		return rv, nil
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		elem, err := decodeValue(value, target.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(target.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Struct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		decoded := reflect.New(target).Elem()
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldValue, has := lookupField(values, field)
+			if !has {
+				continue
+			}
+			decodedField, err := decodeValue(fieldValue, field.Type)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+			decoded.Field(i).Set(decodedField)
+		}
+		return decoded, nil
+	case reflect.Slice:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			break
+		}
+		decoded := reflect.MakeSlice(target, rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, err := decodeValue(rv.Index(i).Interface(), target.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %w", i, err)
+			}
+			decoded.Index(i).Set(item)
+		}
+		return decoded, nil
+	case reflect.Map:
+		values, ok := value.(map[string]interface{})
+		if !ok || target.Key().Kind() != reflect.String {
+			break
+		}
+		decoded := reflect.MakeMapWithSize(target, len(values))
+		for key, item := range values {
+			decodedItem, err := decodeValue(item, target.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %s: %w", key, err)
+			}
+			decoded.SetMapIndex(reflect.ValueOf(key).Convert(target.Key()), decodedItem)
+		}
+		return decoded, nil
+	case reflect.String:
+		if rv.Kind() == reflect.String {
+			return rv.Convert(target), nil
+		}
+	case reflect.Bool:
+		if rv.Kind() == reflect.Bool {
+			return rv.Convert(target), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isNumberKind(rv.Kind()) {
+			return rv.Convert(target), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("cannot decode %v of type %T into %s", value, value, target)
+}
+
+// lookupField finds value of struct field by `graphql:"name"` tag or by case-insensitive name
+func lookupField(values map[string]interface{}, field reflect.StructField) (interface{}, bool) {
+	if name, tagged := field.Tag.Lookup("graphql"); tagged {
+		value, has := values[name]
+		return value, has
+	}
+	if value, has := values[field.Name]; has {
+		return value, true
+	}
+	for name, value := range values {
+		if strings.EqualFold(name, field.Name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
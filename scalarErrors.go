@@ -0,0 +1,333 @@
+package actograph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// errInvalidScalarValue is reported when function of ScalarConfigE returns nil without error
+var errInvalidScalarValue = errors.New("invalid value")
+
+func serializeScalar(cfg ScalarConfigE, value interface{}) (interface{}, error) {
+	serialized, err := cfg.Serialize(value)
+	if err == nil && serialized == nil {
+		err = errInvalidScalarValue
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s cannot represent value %v: %w", cfg.Name, value, err)
+	}
+	return serialized, nil
+}
+
+func parseScalarValue(cfg ScalarConfigE, value interface{}) (interface{}, error) {
+	parsed, err := cfg.ParseValue(value)
+	if err == nil && parsed == nil {
+		err = errInvalidScalarValue
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+func parseScalarLiteral(cfg ScalarConfigE, valueAST ast.Value) (interface{}, error) {
+	parsed, err := cfg.ParseLiteral(valueAST)
+	if err == nil && parsed == nil {
+		err = errInvalidScalarValue
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// otherSpecifiedRules are graphql.SpecifiedRules except ArgumentsOfCorrectTypeRule and
+// DefaultValuesOfCorrectTypeRule, those are replaced by rules reporting errors of scalars.
+// Keep in sync with graphql.SpecifiedRules, TestValidationRules fails when it changes
+var otherSpecifiedRules = []graphql.ValidationRuleFn{
+	graphql.FieldsOnCorrectTypeRule,
+	graphql.FragmentsOnCompositeTypesRule,
+	graphql.KnownArgumentNamesRule,
+	graphql.KnownDirectivesRule,
+	graphql.KnownFragmentNamesRule,
+	graphql.KnownTypeNamesRule,
+	graphql.LoneAnonymousOperationRule,
+	graphql.NoFragmentCyclesRule,
+	graphql.NoUndefinedVariablesRule,
+	graphql.NoUnusedFragmentsRule,
+	graphql.NoUnusedVariablesRule,
+	graphql.OverlappingFieldsCanBeMergedRule,
+	graphql.PossibleFragmentSpreadsRule,
+	graphql.ProvidedNonNullArgumentsRule,
+	graphql.ScalarLeafsRule,
+	graphql.UniqueArgumentNamesRule,
+	graphql.UniqueFragmentNamesRule,
+	graphql.UniqueInputFieldNamesRule,
+	graphql.UniqueOperationNamesRule,
+	graphql.UniqueVariableNamesRule,
+	graphql.VariablesAreInputTypesRule,
+	graphql.VariablesInAllowedPositionRule,
+}
+
+// specifiedRules returns rules of graphql spec with literal rules reporting errors of scalars
+func (agh *Actograph) specifiedRules() []graphql.ValidationRuleFn {
+	rules := make([]graphql.ValidationRuleFn, 0, len(otherSpecifiedRules)+2)
+	rules = append(rules, agh.argumentsOfCorrectTypeRule, agh.defaultValuesOfCorrectTypeRule)
+	return append(rules, otherSpecifiedRules...)
+}
+
+// argumentsOfCorrectTypeRule is graphql.ArgumentsOfCorrectTypeRule with errors of scalars in messages
+func (agh *Actograph) argumentsOfCorrectTypeRule(context *graphql.ValidationContext) *graphql.ValidationRuleInstance {
+	return &graphql.ValidationRuleInstance{
+		VisitorOpts: &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.Argument: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						argAST, ok := p.Node.(*ast.Argument)
+						argDefinition := context.Argument()
+						if !ok || argDefinition == nil {
+							return visitor.ActionSkip, nil
+						}
+						if messages := agh.literalErrors(argDefinition.Type, argAST.Value); len(messages) > 0 {
+							context.ReportError(newValidationError(
+								fmt.Sprintf(`Argument "%v" has invalid value %v.%v`,
+									argAST.Name.Value, printer.Print(argAST.Value), "\n"+strings.Join(messages, "\n")),
+								argAST.Value,
+							))
+						}
+						return visitor.ActionSkip, nil
+					},
+				},
+			},
+		},
+	}
+}
+
+// defaultValuesOfCorrectTypeRule is graphql.DefaultValuesOfCorrectTypeRule with errors of scalars in messages
+func (agh *Actograph) defaultValuesOfCorrectTypeRule(context *graphql.ValidationContext) *graphql.ValidationRuleInstance {
+	skip := visitor.NamedVisitFuncs{
+		Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+			return visitor.ActionSkip, nil
+		},
+	}
+	return &graphql.ValidationRuleInstance{
+		VisitorOpts: &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.VariableDefinition: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						varDefinition, ok := p.Node.(*ast.VariableDefinition)
+						if !ok || varDefinition.DefaultValue == nil {
+							return visitor.ActionSkip, nil
+						}
+						name := varDefinition.Variable.Name.Value
+						defaultValue := varDefinition.DefaultValue
+						inputType := context.InputType()
+						if nonNull, isNonNull := inputType.(*graphql.NonNull); isNonNull {
+							context.ReportError(newValidationError(
+								fmt.Sprintf(`Variable "$%v" of type "%v" is required and will not use the default value. `+
+									`Perhaps you meant to use type "%v".`, name, nonNull, nonNull.OfType),
+								defaultValue,
+							))
+						}
+						if messages := agh.literalErrors(inputType, defaultValue); len(messages) > 0 {
+							context.ReportError(newValidationError(
+								fmt.Sprintf(`Variable "$%v" has invalid default value: %v.%v`,
+									name, printer.Print(defaultValue), "\n"+strings.Join(messages, "\n")),
+								defaultValue,
+							))
+						}
+						return visitor.ActionSkip, nil
+					},
+				},
+				kinds.SelectionSet:       skip,
+				kinds.FragmentDefinition: skip,
+			},
+		},
+	}
+}
+
+// literalErrors returns messages about literal invalid for type, like graphql does,
+// but with errors of scalars
+func (agh *Actograph) literalErrors(inputType graphql.Input, valueAST ast.Value) []string {
+	if _, isNonNull := inputType.(*graphql.NonNull); !isNonNull {
+		// variables are checked by variableErrors
+		if valueAST == nil || valueAST.GetKind() == kinds.Variable {
+			return nil
+		}
+	}
+
+	switch inputType := inputType.(type) {
+	case *graphql.NonNull:
+		if valueAST == nil {
+			return []string{fmt.Sprintf(`Expected "%v", found null.`, inputType)}
+		}
+		return agh.literalErrors(inputType.OfType.(graphql.Input), valueAST)
+	case *graphql.List:
+		itemType := inputType.OfType.(graphql.Input)
+		listAST, isList := valueAST.(*ast.ListValue)
+		if !isList {
+			// single value is accepted as list of one
+			return agh.literalErrors(itemType, valueAST)
+		}
+		var messages []string
+		for i, itemAST := range listAST.Values {
+			for _, message := range agh.literalErrors(itemType, itemAST) {
+				messages = append(messages, fmt.Sprintf(`In element #%v: %v`, i+1, message))
+			}
+		}
+		return messages
+	case *graphql.InputObject:
+		objectAST, isObject := valueAST.(*ast.ObjectValue)
+		if !isObject {
+			return []string{fmt.Sprintf(`Expected "%v", found not an object.`, inputType.Name())}
+		}
+		fields := inputType.Fields()
+		fieldASTs := map[string]ast.Value{}
+		var messages []string
+		for _, fieldAST := range objectAST.Fields {
+			fieldASTs[fieldAST.Name.Value] = fieldAST.Value
+			if _, has := fields[fieldAST.Name.Value]; !has {
+				messages = append(messages, fmt.Sprintf(`In field "%v": Unknown field.`, fieldAST.Name.Value))
+			}
+		}
+		for fieldName, field := range fields {
+			for _, message := range agh.literalErrors(field.Type, fieldASTs[fieldName]) {
+				messages = append(messages, fmt.Sprintf(`In field "%v": %v`, fieldName, message))
+			}
+		}
+		return messages
+	case *graphql.Scalar:
+		if cfg, has := agh.scalarConfigs[inputType.Name()]; has {
+			if _, err := parseScalarLiteral(cfg, valueAST); err != nil {
+				return []string{fmt.Sprintf(`Expected type "%v", found %v: %v.`, inputType.Name(), printer.Print(valueAST), err)}
+			}
+			return nil
+		}
+		if inputType.ParseLiteral(valueAST) == nil {
+			return []string{fmt.Sprintf(`Expected type "%v", found %v.`, inputType.Name(), printer.Print(valueAST))}
+		}
+	case *graphql.Enum:
+		if inputType.ParseLiteral(valueAST) == nil {
+			return []string{fmt.Sprintf(`Expected type "%v", found %v.`, inputType.Name(), printer.Print(valueAST))}
+		}
+	}
+	return nil
+}
+
+// variableErrors reports variables of operation rejected by scalars, other invalid variables are
+// reported by graphql while executing
+func (agh *Actograph) variableErrors(
+	schema *graphql.Schema,
+	doc *ast.Document,
+	operationName string,
+	variables map[string]interface{},
+) []gqlerrors.FormattedError {
+	operation := findOperation(doc, operationName)
+	if operation == nil {
+		return nil
+	}
+
+	var errs []gqlerrors.FormattedError
+	for _, varDefinition := range operation.VariableDefinitions {
+		name := varDefinition.Variable.Name.Value
+		value, has := variables[name]
+		inputType := inputTypeFromAST(schema, varDefinition.Type)
+		if !has || inputType == nil {
+			continue
+		}
+		if messages := agh.valueErrors(inputType, value); len(messages) > 0 {
+			printed, _ := json.Marshal(value)
+			errs = append(errs, gqlerrors.FormatError(newValidationError(
+				fmt.Sprintf(`Variable "$%v" got invalid value %s.%v`, name, printed, "\n"+strings.Join(messages, "\n")),
+				varDefinition,
+			)))
+		}
+	}
+	return errs
+}
+
+// valueErrors returns messages about scalars rejecting value of variable
+func (agh *Actograph) valueErrors(inputType graphql.Input, value interface{}) []string {
+	if value == nil {
+		return nil
+	}
+
+	switch inputType := inputType.(type) {
+	case *graphql.NonNull:
+		return agh.valueErrors(inputType.OfType.(graphql.Input), value)
+	case *graphql.List:
+		itemType := inputType.OfType.(graphql.Input)
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return agh.valueErrors(itemType, value)
+		}
+		var messages []string
+		for i := 0; i < rv.Len(); i++ {
+			for _, message := range agh.valueErrors(itemType, rv.Index(i).Interface()) {
+				messages = append(messages, fmt.Sprintf(`In element #%v: %v`, i+1, message))
+			}
+		}
+		return messages
+	case *graphql.InputObject:
+		object, isObject := value.(map[string]interface{})
+		if !isObject {
+			return nil
+		}
+		var messages []string
+		for fieldName, field := range inputType.Fields() {
+			for _, message := range agh.valueErrors(field.Type, object[fieldName]) {
+				messages = append(messages, fmt.Sprintf(`In field "%v": %v`, fieldName, message))
+			}
+		}
+		return messages
+	case *graphql.Scalar:
+		if cfg, has := agh.scalarConfigs[inputType.Name()]; has {
+			if _, err := parseScalarValue(cfg, value); err != nil {
+				printed, _ := json.Marshal(value)
+				return []string{fmt.Sprintf(`Expected type "%v", found %s: %v.`, inputType.Name(), printed, err)}
+			}
+		}
+	}
+	return nil
+}
+
+func inputTypeFromAST(schema *graphql.Schema, t ast.Type) graphql.Input {
+	switch t := t.(type) {
+	case *ast.NonNull:
+		if ofType := inputTypeFromAST(schema, t.Type); ofType != nil {
+			return graphql.NewNonNull(ofType)
+		}
+	case *ast.List:
+		if ofType := inputTypeFromAST(schema, t.Type); ofType != nil {
+			return graphql.NewList(ofType)
+		}
+	case *ast.Named:
+		if inputType, ok := schema.Type(t.Name.Value).(graphql.Input); ok {
+			return inputType
+		}
+	}
+	return nil
+}
+
+// findOperation returns operation of document to execute
+func findOperation(doc *ast.Document, operationName string) *ast.OperationDefinition {
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		if definition, ok := definition.(*ast.OperationDefinition); ok {
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	return operation
+}
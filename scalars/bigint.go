@@ -0,0 +1,91 @@
+package scalars
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph"
+)
+
+// BigInt is integer of arbitrary size. It is serialized as string, because JSON numbers lose precision
+// out of 53 bits in most clients, and parsed from string or integer. Resolvers get *big.Int, resolved value
+// can be *big.Int, big.Int, any Go integer or string
+var BigInt = actograph.ScalarConfigE{
+	Name:        "BigInt",
+	Description: "The `BigInt` scalar type represents integer of arbitrary size serialized as string.",
+	Serialize: func(value interface{}) (interface{}, error) {
+		i, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return i.String(), nil
+	},
+	ParseValue: func(value interface{}) (interface{}, error) {
+		return toBigInt(value)
+	},
+	ParseLiteral: func(valueAST ast.Value) (interface{}, error) {
+		switch valueAST := valueAST.(type) {
+		case *ast.IntValue:
+			return parseBigInt(valueAST.Value)
+		case *ast.StringValue:
+			return parseBigInt(valueAST.Value)
+		}
+		return nil, fmt.Errorf("expected integer or string literal, got %s", valueAST.GetKind())
+	},
+}
+
+func parseBigInt(s string) (*big.Int, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return i, nil
+}
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch value := value.(type) {
+	case *big.Int:
+		if value == nil {
+			return nil, unsupportedType(value)
+		}
+		return value, nil
+	case big.Int:
+		return &value, nil
+	case string:
+		return parseBigInt(value)
+	case json.Number:
+		return parseBigInt(string(value))
+	case int:
+		return big.NewInt(int64(value)), nil
+	case int8:
+		return big.NewInt(int64(value)), nil
+	case int16:
+		return big.NewInt(int64(value)), nil
+	case int32:
+		return big.NewInt(int64(value)), nil
+	case int64:
+		return big.NewInt(value), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(value)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(value)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(value)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(value)), nil
+	case uint64:
+		return new(big.Int).SetUint64(value), nil
+	case float64:
+		// variables decoded from JSON are float64
+		if value != math.Trunc(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("%v is not an integer", value)
+		}
+		i, _ := big.NewFloat(value).Int(nil)
+		return i, nil
+	}
+	return nil, unsupportedType(value)
+}
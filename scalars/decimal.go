@@ -0,0 +1,98 @@
+package scalars
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph"
+)
+
+var decimalRegexp = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Decimal is exact decimal number serialized as string, e.g. "12.50", and parsed from string, integer or float.
+// Resolvers get validated string to keep precision, resolved value can be string, *big.Float, *big.Rat,
+// float64 or any Go integer
+var Decimal = actograph.ScalarConfigE{
+	Name:        "Decimal",
+	Description: "The `Decimal` scalar type represents exact decimal number serialized as string.",
+	Serialize: func(value interface{}) (interface{}, error) {
+		return toDecimal(value)
+	},
+	ParseValue: func(value interface{}) (interface{}, error) {
+		return toDecimal(value)
+	},
+	ParseLiteral: func(valueAST ast.Value) (interface{}, error) {
+		switch valueAST.(type) {
+		case *ast.IntValue, *ast.FloatValue, *ast.StringValue:
+			return toDecimal(valueAST.GetValue())
+		}
+		return nil, fmt.Errorf("expected number or string literal, got %s", valueAST.GetKind())
+	},
+}
+
+func toDecimal(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		if !decimalRegexp.MatchString(value) {
+			return "", fmt.Errorf("invalid decimal %q", value)
+		}
+		return value, nil
+	case json.Number:
+		return toDecimal(string(value))
+	case float64:
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return "", fmt.Errorf("invalid decimal %v", value)
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case float32:
+		return toDecimal(float64(value))
+	case *big.Float:
+		if value == nil || value.IsInf() {
+			return "", fmt.Errorf("invalid decimal %v", value)
+		}
+		return value.Text('f', -1), nil
+	case *big.Rat:
+		if value == nil {
+			return "", unsupportedType(value)
+		}
+		return ratString(value)
+	}
+	i, err := toBigInt(value)
+	if err != nil {
+		return "", err
+	}
+	return i.String(), nil
+}
+
+// ratString formats rational number as exact decimal, it fails when there is no finite decimal representation
+func ratString(r *big.Rat) (string, error) {
+	// r has finite decimal representation when denominator is 2^a*5^b, then max(a, b) digits are enough
+	denominator := new(big.Int).Set(r.Denom())
+	digits := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		f := big.NewInt(factor)
+		m := new(big.Int)
+		for {
+			q, rem := new(big.Int).QuoRem(denominator, f, m)
+			if rem.Sign() != 0 {
+				break
+			}
+			denominator = q
+			count++
+		}
+		if count > digits {
+			digits = count
+		}
+	}
+	if denominator.Cmp(big.NewInt(1)) != 0 {
+		return "", fmt.Errorf("%v has no finite decimal representation", r)
+	}
+	return r.FloatString(digits), nil
+}
@@ -0,0 +1,101 @@
+package scalars
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationRegexp matches ISO 8601 duration without years and months, those have no fixed length
+var durationRegexp = regexp.MustCompile(`^(-)?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// Duration is ISO 8601 duration without years and months, e.g. "PT1H30M" or "P1DT0.5S". Resolvers get
+// time.Duration, resolved value can be time.Duration, *time.Duration or string
+var Duration = stringScalar(
+	"Duration",
+	"The `Duration` scalar type represents ISO 8601 duration without years and months, e.g. PT1H30M.",
+	"https://en.wikipedia.org/wiki/ISO_8601#Durations",
+	func(s string) (interface{}, error) {
+		return parseDuration(s)
+	},
+	func(value interface{}) (string, error) {
+		switch value := value.(type) {
+		case time.Duration:
+			return formatDuration(value), nil
+		case *time.Duration:
+			if value == nil {
+				return "", unsupportedType(value)
+			}
+			return formatDuration(*value), nil
+		case string:
+			d, err := parseDuration(value)
+			if err != nil {
+				return "", err
+			}
+			return formatDuration(d), nil
+		}
+		return "", unsupportedType(value)
+	},
+)
+
+func parseDuration(s string) (time.Duration, error) {
+	match := durationRegexp.FindStringSubmatch(s)
+	if match == nil || s == "P" || s == "-P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid duration %q, expected ISO 8601 duration without years and months", s)
+	}
+
+	var total float64
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		part := match[i+2]
+		if part == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += value * float64(unit)
+	}
+	if total > math.MaxInt64 {
+		return 0, fmt.Errorf("duration %q is too long", s)
+	}
+	if match[1] == "-" {
+		total = -total
+	}
+	return time.Duration(math.Round(total)), nil
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var sb strings.Builder
+	if d < 0 {
+		sb.WriteString("-")
+		d = -d
+	}
+	sb.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		sb.WriteString(strconv.FormatInt(int64(days), 10) + "D")
+		d -= days * 24 * time.Hour
+	}
+	if d == 0 {
+		return sb.String()
+	}
+	sb.WriteString("T")
+	if hours := d / time.Hour; hours > 0 {
+		sb.WriteString(strconv.FormatInt(int64(hours), 10) + "H")
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		sb.WriteString(strconv.FormatInt(int64(minutes), 10) + "M")
+		d -= minutes * time.Minute
+	}
+	if d > 0 {
+		sb.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return sb.String()
+}
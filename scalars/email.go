@@ -0,0 +1,31 @@
+package scalars
+
+import (
+	"fmt"
+	"net/mail"
+)
+
+// Email is email address without display name, e.g. "user@example.com"
+var Email = stringScalar(
+	"Email",
+	"The `Email` scalar type represents email address.",
+	"https://www.rfc-editor.org/rfc/rfc5322#section-3.4.1",
+	func(s string) (interface{}, error) {
+		return parseEmail(s)
+	},
+	func(value interface{}) (string, error) {
+		s, ok := value.(string)
+		if !ok {
+			return "", unsupportedType(value)
+		}
+		return parseEmail(s)
+	},
+)
+
+func parseEmail(s string) (string, error) {
+	address, err := mail.ParseAddress(s)
+	if err != nil || address.Name != "" || address.Address != s {
+		return "", fmt.Errorf("invalid email address %q", s)
+	}
+	return s, nil
+}
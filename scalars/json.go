@@ -0,0 +1,65 @@
+package scalars
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph"
+)
+
+// JSON is any JSON value. Object and list literals are parsed to map[string]interface{} and []interface{}
+var JSON = actograph.ScalarConfigE{
+	Name:           "JSON",
+	Description:    "The `JSON` scalar type represents any JSON value.",
+	SpecifiedByURL: "https://www.rfc-editor.org/rfc/rfc8259",
+	Serialize: func(value interface{}) (interface{}, error) {
+		return value, nil
+	},
+	ParseValue: func(value interface{}) (interface{}, error) {
+		return value, nil
+	},
+	ParseLiteral: jsonFromAST,
+}
+
+func jsonFromAST(valueAST ast.Value) (interface{}, error) {
+	switch valueAST := valueAST.(type) {
+	case *ast.ObjectValue:
+		object := make(map[string]interface{}, len(valueAST.Fields))
+		for _, field := range valueAST.Fields {
+			value, err := jsonFromAST(field.Value)
+			if err != nil {
+				return nil, err
+			}
+			object[field.Name.Value] = value
+		}
+		return object, nil
+	case *ast.ListValue:
+		list := make([]interface{}, len(valueAST.Values))
+		for i, item := range valueAST.Values {
+			value, err := jsonFromAST(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case *ast.IntValue:
+		if i, err := strconv.ParseInt(valueAST.Value, 10, 64); err == nil && i >= math.MinInt && i <= math.MaxInt {
+			return int(i), nil
+		}
+		return strconv.ParseFloat(valueAST.Value, 64)
+	case *ast.FloatValue:
+		return strconv.ParseFloat(valueAST.Value, 64)
+	case *ast.StringValue:
+		return valueAST.Value, nil
+	case *ast.BooleanValue:
+		return valueAST.Value, nil
+	case *ast.EnumValue:
+		return valueAST.Value, nil
+	}
+	// graphql-go doesn't substitute variables inside literals of scalars
+	return nil, fmt.Errorf("unsupported literal %s", valueAST.GetKind())
+}
@@ -0,0 +1,57 @@
+// Package scalars provides validated custom scalars ready to register with Actograph.RegisterScalarsE:
+//
+//	agh.RegisterScalarsE(scalars.All()...)
+//
+// every scalar should be declared in schema too, e.g. `scalar UUID`. Invalid values are reported
+// as GraphQL errors
+package scalars
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/actord/actograph"
+)
+
+// All returns every scalar of package
+func All() []actograph.ScalarConfigE {
+	return []actograph.ScalarConfigE{JSON, UUID, Email, URL, BigInt, Decimal, Date, Time, Duration}
+}
+
+// stringScalar makes scalar represented by string in requests and responses. parse validates string
+// and returns value passed to resolvers, format makes string from resolved value
+func stringScalar(
+	name string,
+	description string,
+	specifiedByURL string,
+	parse func(s string) (interface{}, error),
+	format func(value interface{}) (string, error),
+) actograph.ScalarConfigE {
+	parseValue := func(value interface{}) (interface{}, error) {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", value)
+		}
+		return parse(s)
+	}
+	return actograph.ScalarConfigE{
+		Name:           name,
+		Description:    description,
+		SpecifiedByURL: specifiedByURL,
+		Serialize: func(value interface{}) (interface{}, error) {
+			return format(value)
+		},
+		ParseValue: parseValue,
+		ParseLiteral: func(valueAST ast.Value) (interface{}, error) {
+			if valueAST.GetKind() != "StringValue" {
+				return nil, fmt.Errorf("expected string literal, got %s", valueAST.GetKind())
+			}
+			return parseValue(valueAST.GetValue())
+		},
+	}
+}
+
+func unsupportedType(value interface{}) error {
+	return fmt.Errorf("unsupported type %T", value)
+}
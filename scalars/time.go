@@ -0,0 +1,65 @@
+package scalars
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04:05.999999999"
+)
+
+// Date is calendar date without time, e.g. "2006-01-02". Resolvers get time.Time at midnight UTC,
+// resolved value can be time.Time, *time.Time or string
+var Date = stringScalar(
+	"Date",
+	"The `Date` scalar type represents calendar date in format YYYY-MM-DD.",
+	"https://www.rfc-editor.org/rfc/rfc3339#section-5.6",
+	func(s string) (interface{}, error) {
+		return parseTime(dateLayout, s)
+	},
+	func(value interface{}) (string, error) {
+		return formatTime(dateLayout, value)
+	},
+)
+
+// Time is time of day without date and time zone, e.g. "15:04:05" or "15:04:05.123". Resolvers get time.Time
+// of zero date in UTC, resolved value can be time.Time, *time.Time or string
+var Time = stringScalar(
+	"Time",
+	"The `Time` scalar type represents time of day in format hh:mm:ss with optional fractional seconds.",
+	"https://www.rfc-editor.org/rfc/rfc3339#section-5.6",
+	func(s string) (interface{}, error) {
+		return parseTime(timeLayout, s)
+	},
+	func(value interface{}) (string, error) {
+		return formatTime(timeLayout, value)
+	},
+)
+
+func parseTime(layout string, s string) (time.Time, error) {
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value %q, expected format %s", s, layout)
+	}
+	return t, nil
+}
+
+func formatTime(layout string, value interface{}) (string, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return value.Format(layout), nil
+	case *time.Time:
+		if value == nil {
+			return "", unsupportedType(value)
+		}
+		return value.Format(layout), nil
+	case string:
+		if _, err := parseTime(layout, value); err != nil {
+			return "", err
+		}
+		return value, nil
+	}
+	return "", unsupportedType(value)
+}
@@ -0,0 +1,53 @@
+package scalars
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// URL is absolute URL, e.g. "https://example.com/path". Resolvers get *url.URL, resolved value can be
+// string, url.URL or *url.URL
+var URL = stringScalar(
+	"URL",
+	"The `URL` scalar type represents absolute URL.",
+	"https://www.rfc-editor.org/rfc/rfc3986",
+	func(s string) (interface{}, error) {
+		return parseURL(s)
+	},
+	func(value interface{}) (string, error) {
+		switch value := value.(type) {
+		case string:
+			u, err := parseURL(value)
+			if err != nil {
+				return "", err
+			}
+			return u.String(), nil
+		case url.URL:
+			return formatURL(&value)
+		case *url.URL:
+			if value == nil {
+				return "", unsupportedType(value)
+			}
+			return formatURL(value)
+		}
+		return "", unsupportedType(value)
+	},
+)
+
+func parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q", s)
+	}
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("URL %q is not absolute", s)
+	}
+	return u, nil
+}
+
+func formatURL(u *url.URL) (string, error) {
+	if !u.IsAbs() {
+		return "", fmt.Errorf("URL %q is not absolute", u)
+	}
+	return u.String(), nil
+}
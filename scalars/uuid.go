@@ -0,0 +1,44 @@
+package scalars
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// UUID is universally unique identifier in canonical form "123e4567-e89b-12d3-a456-426614174000".
+// Resolvers get lowercase string, resolved value can be string, [16]byte or fmt.Stringer
+var UUID = stringScalar(
+	"UUID",
+	"The `UUID` scalar type represents universally unique identifier in canonical textual form.",
+	"https://www.rfc-editor.org/rfc/rfc4122",
+	func(s string) (interface{}, error) {
+		return parseUUID(s)
+	},
+	func(value interface{}) (string, error) {
+		switch value := value.(type) {
+		case string:
+			return parseUUID(value)
+		case [16]byte:
+			return formatUUID(value), nil
+		case fmt.Stringer:
+			return parseUUID(value.String())
+		}
+		return "", unsupportedType(value)
+	},
+)
+
+func parseUUID(s string) (string, error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return "", fmt.Errorf("invalid UUID %q", s)
+	}
+	if _, err := hex.DecodeString(strings.ReplaceAll(s, "-", "")); err != nil {
+		return "", fmt.Errorf("invalid UUID %q", s)
+	}
+	return strings.ToLower(s), nil
+}
+
+func formatUUID(b [16]byte) string {
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
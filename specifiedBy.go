@@ -0,0 +1,50 @@
+package actograph
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// specifiedByDirectiveName is hardcoded directive that provides URL of scalar specification:
+//
+//	directive @specifiedBy(url: String!) on SCALAR
+//
+// graphql-go has no __Type.specifiedByURL yet, so URL is provided by Executable.SpecifiedByURL,
+// see also ScalarConfig.SpecifiedByURL
+const specifiedByDirectiveName = "specifiedBy"
+
+// specifiedByURLs maps names of scalars to URLs of their specifications set by ScalarConfig.SpecifiedByURL
+// or @specifiedBy
+func (agh *Actograph) specifiedByURLs() map[string]string {
+	urls := map[string]string{}
+	for name, cfg := range agh.scalarConfigs {
+		if cfg.SpecifiedByURL != "" {
+			urls[name] = cfg.SpecifiedByURL
+		}
+	}
+	for name, cfg := range agh.legacyScalarConfigs {
+		if cfg.SpecifiedByURL != "" {
+			urls[name] = cfg.SpecifiedByURL
+		}
+	}
+	for _, scalarDefinition := range agh.declaredScalars {
+		if url := specifiedByURL(scalarDefinition.Directives); url != "" {
+			urls[scalarDefinition.Name] = url
+		}
+	}
+	return urls
+}
+
+// specifiedByURL returns url argument of @specifiedBy
+func specifiedByURL(directives []*ast.Directive) string {
+	for _, dir := range directives {
+		if dir.Name.Value != specifiedByDirectiveName {
+			continue
+		}
+		for _, arg := range dir.Arguments {
+			if url, ok := arg.Value.(*ast.StringValue); ok && arg.Name.Value == "url" {
+				return url.Value
+			}
+		}
+	}
+	return ""
+}
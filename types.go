@@ -27,6 +27,10 @@ type RequestQuery struct {
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// Audience selects variant of schema (see Actograph.SchemaFor) used to execute request.
+	// Empty audience means full schema.
+	Audience string
 }
 
 // Result has the response, errors and extensions from the resolved schema
@@ -47,15 +51,48 @@ type ParseLiteralFn func(valueAST ast.Value) interface{}
 
 // ScalarConfig options for creating a new GraphQLScalar
 type ScalarConfig struct {
-	Name         string
-	Description  string
-	Serialize    SerializeFn
-	ParseValue   ParseValueFn
-	ParseLiteral ParseLiteralFn
+	Name        string
+	Description string
+	// SpecifiedByURL is returned by Executable.SpecifiedByURL, @specifiedBy(url:) of scalar
+	// declaration in schema overrides it. Optional
+	SpecifiedByURL string
+	Serialize      SerializeFn
+	ParseValue     ParseValueFn
+	ParseLiteral   ParseLiteralFn
+}
+
+// SerializeFnE is a function type for serializing a GraphQLScalar type value with error
+type SerializeFnE func(value interface{}) (interface{}, error)
+
+// ParseValueFnE is a function type for parsing the value of a GraphQLScalar type with error
+type ParseValueFnE func(value interface{}) (interface{}, error)
+
+// ParseLiteralFnE is a function type for parsing the literal value of a GraphQLScalar type with error
+type ParseLiteralFnE func(valueAST ast.Value) (interface{}, error)
+
+// ScalarConfigE options for creating a new GraphQLScalar which functions report errors. Invalid variables
+// and literals are reported as validation errors, failed serialization as error of field.
+// nil value returned without error is an error too
+type ScalarConfigE struct {
+	Name        string
+	Description string
+	// SpecifiedByURL is returned by Executable.SpecifiedByURL, @specifiedBy(url:) of scalar
+	// declaration in schema overrides it. Optional
+	SpecifiedByURL string
+	Serialize      SerializeFnE
+	ParseValue     ParseValueFnE
+	ParseLiteral   ParseLiteralFnE
 }
 
 type ScalarDefinition struct {
 	Name        string
 	Description string
-	// TODO: Directives
+	Directives  []*ast.Directive // only hardcoded directives (like @visibility) are supported now
+	Loc         *ast.Location    // location of declaration in schema
+}
+
+// Typenamer can be implemented by values resolved for interface types to tell name of their object type,
+// otherwise value should be map[string]interface{} with "__typename" key
+type Typenamer interface {
+	GraphQLTypename() string
 }
@@ -0,0 +1,370 @@
+package actograph
+
+import (
+	"sort"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// visibilityDirectiveName is hardcoded directive that limits audiences of schema variant (see SchemaFor):
+//
+//	directive @visibility(audiences: [String!]!) on OBJECT | FIELD_DEFINITION | ARGUMENT_DEFINITION | ENUM
+//	    | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION | UNION | INTERFACE | SCALAR
+//
+// element without @visibility is visible for every audience
+const visibilityDirectiveName = "visibility"
+
+// isVisible reports is element with directives visible for agh.audience. Everything is visible for empty audience,
+// except elements marked by @internal when agh.hideInternal is set
+func (agh *Actograph) isVisible(directives []*ast.Directive) bool {
+	for _, dir := range directives {
+		if agh.hideInternal && dir.Name.Value == internalDirectiveName {
+			return false
+		}
+	}
+	if agh.audience == "" {
+		return true
+	}
+
+	for _, dir := range directives {
+		if dir.Name.Value != visibilityDirectiveName {
+			continue
+		}
+		audiences, has := directiveAudiences(dir)
+		if !has {
+			continue
+		}
+		for _, audience := range audiences {
+			if audience == agh.audience {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// directiveAudiences returns audiences argument of @visibility usage
+func directiveAudiences(dir *ast.Directive) ([]string, bool) {
+	for _, arg := range dir.Arguments {
+		if arg.Name.Value != "audiences" {
+			continue
+		}
+		values, ok := arg.Value.(*ast.ListValue)
+		if !ok {
+			// single value can be passed instead of list
+			values = &ast.ListValue{Values: []ast.Value{arg.Value}}
+		}
+		audiences := make([]string, 0, len(values.Values))
+		for _, value := range values.Values {
+			audience, _ := value.GetValue().(string)
+			audiences = append(audiences, audience)
+		}
+		return audiences, true
+	}
+	return nil, false
+}
+
+// visibilityAudiences returns sorted audiences listed by @visibility usages, every audience has own schema variant
+func (agh *Actograph) visibilityAudiences() []string {
+	listed := map[string]bool{}
+	agh.walkDirectives(func(directives []*ast.Directive) {
+		for _, dir := range directives {
+			if dir.Name.Value != visibilityDirectiveName {
+				continue
+			}
+			audiences, _ := directiveAudiences(dir)
+			for _, audience := range audiences {
+				listed[audience] = true
+			}
+		}
+	})
+
+	audiences := make([]string, 0, len(listed))
+	for audience := range listed {
+		audiences = append(audiences, audience)
+	}
+	sort.Strings(audiences)
+	return audiences
+}
+
+// walkDirectives calls visit with directives of every type, field, argument, input field and enum value
+func (agh *Actograph) walkDirectives(visit func(directives []*ast.Directive)) {
+	visitArgs := func(args []*ast.InputValueDefinition) {
+		for _, arg := range args {
+			visit(arg.Directives)
+		}
+	}
+
+	for name, definition := range agh.objectDefinitions {
+		visit(definition.Directives)
+		for _, ext := range agh.extensionDefinitions[name] {
+			visit(ext.Definition.Directives)
+		}
+		for _, field := range agh.objectFields(name, definition) {
+			visit(field.Directives)
+			visitArgs(field.Arguments)
+		}
+	}
+	for _, definition := range agh.inputObjectDefinitions {
+		visit(definition.Directives)
+		visitArgs(definition.Fields)
+	}
+	for _, definition := range agh.enumDefinitions {
+		visit(definition.Directives)
+		for _, value := range definition.Values {
+			visit(value.Directives)
+		}
+	}
+	for _, definition := range agh.unionDefinitions {
+		visit(definition.Directives)
+	}
+	for _, definition := range agh.interfaceDefinitions {
+		visit(definition.Directives)
+		for _, field := range definition.Fields {
+			visit(field.Directives)
+			visitArgs(field.Arguments)
+		}
+	}
+	for _, definition := range agh.declaredScalars {
+		visit(definition.Directives)
+	}
+}
+
+// isTypeVisible reports is named type served in schema variant of agh.audience
+func (agh *Actograph) isTypeVisible(t ast.Type) bool {
+	return !agh.hiddenTypes[namedTypeName(t)]
+}
+
+// isFieldVisible reports is field served in schema variant of agh.audience: field itself, its type
+// and required arguments should be visible
+func (agh *Actograph) isFieldVisible(fieldDefinition *ast.FieldDefinition) bool {
+	if !agh.isVisible(fieldDefinition.Directives) || !agh.isTypeVisible(fieldDefinition.Type) {
+		return false
+	}
+	for _, arg := range fieldDefinition.Arguments {
+		if !agh.isArgumentVisible(arg) && isRequired(arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// isArgumentVisible reports is argument or input field served in schema variant of agh.audience
+func (agh *Actograph) isArgumentVisible(arg *ast.InputValueDefinition) bool {
+	return agh.isVisible(arg.Directives) && agh.isTypeVisible(arg.Type)
+}
+
+func isRequired(arg *ast.InputValueDefinition) bool {
+	_, isNonNull := arg.Type.(*ast.NonNull)
+	return isNonNull && arg.DefaultValue == nil
+}
+
+// computeHiddenTypes fills agh.hiddenTypes for agh.audience. Type is hidden by @visibility, when nothing left
+// in it after hiding (object without fields, union without types, etc.), so computation repeats until nothing changed,
+// and when it can't be reached from root types anymore (see hideUnreachableTypes)
+func (agh *Actograph) computeHiddenTypes() {
+	agh.hiddenTypes = map[string]bool{}
+	if agh.audience == "" && !agh.hideInternal {
+		return
+	}
+
+	hide := func(name string, directives []*ast.Directive) {
+		if !agh.isVisible(directives) {
+			agh.hiddenTypes[name] = true
+		}
+	}
+	for name, definition := range agh.objectDefinitions {
+		hide(name, definition.Directives)
+		for _, ext := range agh.extensionDefinitions[name] {
+			hide(name, ext.Definition.Directives)
+		}
+	}
+	for name, definition := range agh.inputObjectDefinitions {
+		hide(name, definition.Directives)
+	}
+	for name, definition := range agh.enumDefinitions {
+		hide(name, definition.Directives)
+	}
+	for name, definition := range agh.unionDefinitions {
+		hide(name, definition.Directives)
+	}
+	for name, definition := range agh.interfaceDefinitions {
+		hide(name, definition.Directives)
+	}
+	for name, definition := range agh.declaredScalars {
+		hide(name, definition.Directives)
+	}
+
+	for changed := true; changed; {
+		changed = false
+		hideEmpty := func(name string, empty bool) {
+			if empty && !agh.hiddenTypes[name] {
+				agh.hiddenTypes[name] = true
+				changed = true
+			}
+		}
+
+		for name, definition := range agh.objectDefinitions {
+			visibleFields := 0
+			for _, field := range agh.objectFields(name, definition) {
+				if agh.isFieldVisible(field) {
+					visibleFields++
+				}
+			}
+			hideEmpty(name, visibleFields == 0)
+		}
+		for name, definition := range agh.inputObjectDefinitions {
+			visibleFields := 0
+			hasHiddenRequired := false
+			for _, field := range definition.Fields {
+				if agh.isArgumentVisible(field) {
+					visibleFields++
+				} else if isRequired(field) {
+					hasHiddenRequired = true
+				}
+			}
+			hideEmpty(name, visibleFields == 0 || hasHiddenRequired)
+		}
+		for name, definition := range agh.enumDefinitions {
+			visibleValues := 0
+			for _, value := range definition.Values {
+				if agh.isVisible(value.Directives) {
+					visibleValues++
+				}
+			}
+			hideEmpty(name, visibleValues == 0)
+		}
+		for name, definition := range agh.unionDefinitions {
+			visibleTypes := 0
+			for _, unionType := range definition.Types {
+				if agh.isTypeVisible(unionType) {
+					visibleTypes++
+				}
+			}
+			hideEmpty(name, visibleTypes == 0)
+		}
+		for name, definition := range agh.interfaceDefinitions {
+			visibleFields := 0
+			for _, field := range definition.Fields {
+				if agh.isFieldVisible(field) {
+					visibleFields++
+				}
+			}
+			hideEmpty(name, visibleFields == 0)
+		}
+	}
+
+	agh.hideUnreachableTypes()
+}
+
+// hideUnreachableTypes hides types that can't be reached from root operation types and arguments of executable
+// directives through visible fields, arguments, interfaces, their implementations and union types
+func (agh *Actograph) hideUnreachableTypes() {
+	reachable := map[string]bool{}
+	var reach func(name string)
+	reachFields := func(fields []*ast.FieldDefinition) {
+		for _, field := range fields {
+			if !agh.isFieldVisible(field) {
+				continue
+			}
+			reach(namedTypeName(field.Type))
+			for _, arg := range field.Arguments {
+				if agh.isArgumentVisible(arg) {
+					reach(namedTypeName(arg.Type))
+				}
+			}
+		}
+	}
+	reach = func(name string) {
+		if name == "" || reachable[name] || agh.hiddenTypes[name] {
+			return
+		}
+		reachable[name] = true
+
+		if definition, has := agh.objectDefinitions[name]; has {
+			reachFields(agh.objectFields(name, definition))
+			for _, iface := range agh.objectInterfaces(name, definition) {
+				reach(iface)
+			}
+		}
+		if definition, has := agh.interfaceDefinitions[name]; has {
+			reachFields(definition.Fields)
+			for objName, objDefinition := range agh.objectDefinitions {
+				for _, iface := range agh.objectInterfaces(objName, objDefinition) {
+					if iface == name {
+						reach(objName)
+					}
+				}
+			}
+		}
+		if definition, has := agh.unionDefinitions[name]; has {
+			for _, unionType := range definition.Types {
+				reach(unionType.Name.Value)
+			}
+		}
+		if definition, has := agh.inputObjectDefinitions[name]; has {
+			for _, field := range definition.Fields {
+				if agh.isArgumentVisible(field) {
+					reach(namedTypeName(field.Type))
+				}
+			}
+		}
+	}
+
+	for _, ot := range agh.schema.OperationTypes {
+		reach(ot.Type.Name.Value)
+	}
+	for _, name := range agh.executableDirectiveNames() {
+		for _, arg := range agh.directiveDefinitions[name].Arguments {
+			reach(namedTypeName(arg.Type))
+		}
+	}
+
+	hideUnreached := func(name string) {
+		if !reachable[name] {
+			agh.hiddenTypes[name] = true
+		}
+	}
+	for name := range agh.objectDefinitions {
+		hideUnreached(name)
+	}
+	for name := range agh.interfaceDefinitions {
+		hideUnreached(name)
+	}
+	for name := range agh.unionDefinitions {
+		hideUnreached(name)
+	}
+	for name := range agh.inputObjectDefinitions {
+		hideUnreached(name)
+	}
+	for name := range agh.enumDefinitions {
+		hideUnreached(name)
+	}
+	for name := range agh.declaredScalars {
+		hideUnreached(name)
+	}
+}
+
+// objectFields returns fields of object with fields of its extensions
+func (agh *Actograph) objectFields(name string, definition *ast.ObjectDefinition) []*ast.FieldDefinition {
+	fields := definition.Fields
+	for _, ext := range agh.extensionDefinitions[name] {
+		fields = append(fields[:len(fields):len(fields)], ext.Definition.Fields...)
+	}
+	return fields
+}
+
+// objectInterfaces returns names of interfaces implemented by object or its extensions
+func (agh *Actograph) objectInterfaces(name string, definition *ast.ObjectDefinition) []string {
+	interfaces := make([]string, 0, len(definition.Interfaces))
+	for _, iface := range definition.Interfaces {
+		interfaces = append(interfaces, iface.Name.Value)
+	}
+	for _, ext := range agh.extensionDefinitions[name] {
+		for _, iface := range ext.Definition.Interfaces {
+			interfaces = append(interfaces, iface.Name.Value)
+		}
+	}
+	return interfaces
+}